@@ -1,11 +1,13 @@
 package sdkdist
 
 import (
+	"encoding/json"
 	"net/http"
 	"reflect"
 	"strings"
 	"unicode"
 
+	"github.com/rubikorg/blocks/checker"
 	"github.com/rubikorg/rubik"
 )
 
@@ -17,6 +19,12 @@ type Pair struct {
 	Key        string
 	IsOptional bool
 	Type       string
+	// Enum holds the allowed values when the checker rules of the
+	// field restrict them, generators can emit them as union types
+	Enum []interface{}
+	// Rules are the checker rules of the field as JSON Schema keywords
+	// for client-side validation
+	Rules map[string]interface{}
 }
 
 // TsRoute is the route information for single route
@@ -87,22 +95,27 @@ func TransformTree(
 				tsRoute.Method = route.Method
 			}
 			num := values.NumField()
+			rules, _ := checker.DescribeEntity(route.Entity)
 
 			// add entity data pairs
 			for i := 0; i < num; i++ {
 				field := values.Type().Field(i)
 
 				tag := field.Tag.Get("rubik")
-				key, medium := getRequestField(field.Name, tag)
+				key, medium := RequestField(field.Name, tag)
 				typ := typeConverterFunc(field.Type.Name(), field)
 				if typ == "-1" {
 					continue
 				}
 
+				keywords := checker.Keywords(
+					rules.Lookup(key, strings.Split(field.Tag.Get("json"), ",")[0], field.Name))
 				pair := Pair{
 					Key:        key,
-					IsOptional: true,
+					IsOptional: keywords["required"] != true,
 					Type:       typ,
+					Enum:       toList(keywords["enum"]),
+					Rules:      keywords,
 				}
 				switch medium {
 				case "body":
@@ -126,9 +139,51 @@ func TransformTree(
 	return templateData
 }
 
-// getRequestField takes the original field name and rubik struct tag and
-// returns (key_name, medium)
-func getRequestField(ogName string, tag string) (string, string) {
+// RulesJSON returns the checker rules of the route as a JSON object
+// keyed by medium and field, i.e: {"body":{"name":{"minLength":3}}}
+func (t TreeRoute) RulesJSON() string {
+	all := make(map[string]map[string]map[string]interface{})
+	for medium, pairs := range map[string][]Pair{
+		"form": t.Form, "body": t.Body, "query": t.Query, "param": t.Param,
+	} {
+		for _, p := range pairs {
+			if len(p.Rules) == 0 {
+				continue
+			}
+			if all[medium] == nil {
+				all[medium] = make(map[string]map[string]interface{})
+			}
+			all[medium][p.Key] = p.Rules
+		}
+	}
+
+	b, err := json.Marshal(all)
+	if err != nil {
+		return "{}"
+	}
+
+	return string(b)
+}
+
+// toList turns the values of an enum rule into a list
+func toList(values interface{}) []interface{} {
+	rv := reflect.ValueOf(values)
+	if values == nil || (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) {
+		return nil
+	}
+
+	list := make([]interface{}, rv.Len())
+	for i := range list {
+		list[i] = rv.Index(i).Interface()
+	}
+
+	return list
+}
+
+// RequestField takes the original field name and rubik struct tag and
+// returns (key_name, medium), the swagger block uses it too so that docs
+// and SDKs name the fields alike
+func RequestField(ogName string, tag string) (string, string) {
 	// TODO: handle optional entity fields
 	key := uncapitalize(ogName)
 	constTag := strings.ReplaceAll(tag, "!", "")
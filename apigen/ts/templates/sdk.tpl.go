@@ -77,6 +77,8 @@ export interface {{ $route.EntityName }} extends Entity {
 	}
 	{{- end }}
 }
+
+export const {{ $route.EntityName }}Rules = {{ $route.RulesJSON }};
 {{end}}
 // @class {{ .RouterName }}Api
 export class {{ .RouterName }}Api {
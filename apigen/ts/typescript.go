@@ -2,6 +2,7 @@ package apigen
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -43,6 +44,17 @@ func (TSGenPlugin) OnPlug(app *r.App) error {
 	}
 
 	var templateData = sdkdist.TransformTree(app.RouteTree, getTsTypeEquivalent)
+	for _, data := range templateData {
+		for _, route := range data.Routes {
+			for _, pairs := range [][]sdkdist.Pair{route.Form, route.Body, route.Query, route.Param} {
+				for i := range pairs {
+					if union := getTsUnion(pairs[i].Enum); union != "" {
+						pairs[i].Type = union
+					}
+				}
+			}
+		}
+	}
 
 	outDir := conf.OutDir
 
@@ -164,6 +176,21 @@ func getTsTypeEquivalent(goType string, value reflect.StructField) string {
 	}
 }
 
+// getTsUnion turns the allowed values of a field into a union of literal
+// types like "admin" | "user"
+func getTsUnion(values []interface{}) string {
+	var literals []string
+	for _, v := range values {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		literals = append(literals, string(b))
+	}
+
+	return strings.Join(literals, " | ")
+}
+
 func init() {
 	r.Plug(TSGenPlugin{})
 }
@@ -0,0 +1,437 @@
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+)
+
+// DefaultLocale is the locale used when no catalog matches the requested
+// one
+const DefaultLocale = "en"
+
+// Message is the translation of one rule code. Text is used for rules
+// without a count, Plural holds the forms keyed by CLDR plural category
+// (zero, one, two, few, many, other) and Count names the parameter which
+// picks the form, i.e: `min` for `str.min`. Messages can use `{field}`
+// and `{<param>}` placeholders
+type Message struct {
+	Text   string
+	Plural map[string]string
+	Count  string
+}
+
+// Catalog holds the messages of one locale keyed by rule code
+type Catalog map[string]Message
+
+var pluralKeys = map[string]bool{
+	"zero": true, "one": true, "two": true, "few": true, "many": true, "other": true,
+}
+
+var catalogs = struct {
+	sync.RWMutex
+	m map[string]Catalog
+}{m: map[string]Catalog{
+	DefaultLocale: {
+		"str.min": {Count: "min", Plural: map[string]string{
+			"one":   "{field} needs at least {min} character",
+			"other": "{field} needs at least {min} characters",
+		}},
+		"str.max": {Count: "max", Plural: map[string]string{
+			"one":   "{field} allows at most {max} character",
+			"other": "{field} allows at most {max} characters",
+		}},
+		"str.max_bytes": {Count: "max", Plural: map[string]string{
+			"one":   "{field} allows at most {max} byte",
+			"other": "{field} allows at most {max} bytes",
+		}},
+	},
+}}
+
+// RegisterCatalog adds the messages of the catalog to the given locale,
+// existing messages of the same codes are replaced
+func RegisterCatalog(locale string, c Catalog) {
+	locale = normalizeLocale(locale)
+
+	catalogs.Lock()
+	defer catalogs.Unlock()
+	existing, ok := catalogs.m[locale]
+	if !ok {
+		existing = make(Catalog)
+		catalogs.m[locale] = existing
+	}
+
+	for code, msg := range c {
+		existing[code] = msg
+	}
+}
+
+// LoadCatalog reads a JSON or TOML file of messages and registers it for
+// the given locale. The file maps rule codes to either a string or a
+// table of plural forms:
+//
+//	{
+//	  "required": "{field} est obligatoire",
+//	  "str.min": {"count": "min", "one": "...", "other": "..."}
+//	}
+func LoadCatalog(locale, path string) error {
+	raw := make(map[string]interface{})
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(b, &raw); err != nil {
+			return fmt.Errorf("checker: catalog %s: %v", path, err)
+		}
+	case ".toml":
+		if _, err := toml.DecodeFile(path, &raw); err != nil {
+			return fmt.Errorf("checker: catalog %s: %v", path, err)
+		}
+	default:
+		return fmt.Errorf("checker: catalog %s must be a .json or .toml file", path)
+	}
+
+	c := make(Catalog)
+	if err := readCatalog(c, "", raw); err != nil {
+		return fmt.Errorf("checker: catalog %s: %v", path, err)
+	}
+
+	RegisterCatalog(locale, c)
+	return nil
+}
+
+// LoadCatalogs loads every .json and .toml file of dir as a catalog, the
+// name of the file is its locale, i.e: `fr.json` or `pt-BR.toml`
+func LoadCatalogs(dir string) error {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		ext := strings.ToLower(filepath.Ext(f.Name()))
+		if f.IsDir() || (ext != ".json" && ext != ".toml") {
+			continue
+		}
+
+		locale := strings.TrimSuffix(f.Name(), filepath.Ext(f.Name()))
+		err := LoadCatalog(locale, filepath.Join(dir, f.Name()))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readCatalog walks the decoded file, nested tables which are not plural
+// forms are flattened into dotted codes so that TOML files can use
+// `[str]` tables instead of quoted keys
+func readCatalog(c Catalog, prefix string, raw map[string]interface{}) error {
+	for k, v := range raw {
+		code := k
+		if prefix != "" {
+			code = prefix + "." + k
+		}
+
+		switch val := v.(type) {
+		case string:
+			c[code] = Message{Text: val}
+		case map[string]interface{}:
+			if !isPluralTable(val) {
+				if err := readCatalog(c, code, val); err != nil {
+					return err
+				}
+				continue
+			}
+
+			msg := Message{Plural: make(map[string]string)}
+			for form, text := range val {
+				s, ok := text.(string)
+				if !ok {
+					return fmt.Errorf("%s.%s must be a string", code, form)
+				}
+
+				switch form {
+				case "count":
+					msg.Count = s
+				case "text":
+					msg.Text = s
+				default:
+					msg.Plural[form] = s
+				}
+			}
+			c[code] = msg
+		default:
+			return fmt.Errorf("%s must be a string or a table of plural forms", code)
+		}
+	}
+
+	return nil
+}
+
+func isPluralTable(m map[string]interface{}) bool {
+	for k := range m {
+		if k != "count" && k != "text" && !pluralKeys[k] {
+			return false
+		}
+	}
+
+	return len(m) > 0
+}
+
+// Localize translates the messages of a validation error into the given
+// locale. It falls back to the base language, then to english and then
+// to the default message of the error. Errors which are not validation
+// errors are returned untouched
+func Localize(err error, locale string) error {
+	switch e := err.(type) {
+	case ValidationError:
+		return Translate(e, locale)
+	case ValidationErrors:
+		localized := make(ValidationErrors, len(e))
+		for i, ve := range e {
+			localized[i] = Translate(ve, locale)
+		}
+		return localized
+	}
+
+	return err
+}
+
+// Translate returns a copy of the error with its message in the given
+// locale
+func Translate(ve ValidationError, locale string) ValidationError {
+	for _, l := range localeChain(locale) {
+		catalogs.RLock()
+		msg, ok := catalogs.m[l][ve.Code]
+		catalogs.RUnlock()
+		if !ok {
+			continue
+		}
+
+		text := msg.pick(l, ve.Params)
+		if text == "" {
+			continue
+		}
+
+		ve.Message = interpolate(text, ve.Field, ve.Params)
+		return ve
+	}
+
+	return ve
+}
+
+// LocaleFromRequest picks the best locale for the request. An explicit
+// `locale` query parameter wins over the Accept-Language header, only
+// locales with a registered catalog or whose base language has one are
+// chosen
+func LocaleFromRequest(req *http.Request) string {
+	if req == nil {
+		return DefaultLocale
+	}
+
+	if l := req.URL.Query().Get("locale"); l != "" {
+		if locale, ok := catalogLocale(l); ok {
+			return locale
+		}
+	}
+
+	for _, l := range parseAcceptLanguage(req.Header.Get("Accept-Language")) {
+		if locale, ok := catalogLocale(l); ok {
+			return locale
+		}
+	}
+
+	return DefaultLocale
+}
+
+// catalogLocale returns the locale or its base language, whichever has a
+// catalog first
+func catalogLocale(l string) (string, bool) {
+	for _, candidate := range []string{l, baseLanguage(l)} {
+		if hasCatalog(candidate) {
+			return normalizeLocale(candidate), true
+		}
+	}
+
+	return "", false
+}
+
+func (m Message) pick(locale string, params Params) string {
+	if len(m.Plural) == 0 || m.Count == "" {
+		return m.Text
+	}
+
+	n, err := AsInt(params[m.Count])
+	if err != nil {
+		return m.Text
+	}
+
+	if text, ok := m.Plural[pluralCategory(locale, n)]; ok {
+		return text
+	}
+
+	if text, ok := m.Plural["other"]; ok {
+		return text
+	}
+
+	return m.Text
+}
+
+func interpolate(text, field string, params Params) string {
+	pairs := []string{"{field}", field}
+	for k, v := range params {
+		pairs = append(pairs, "{"+k+"}", paramText(v))
+	}
+
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+func paramText(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		parts := make([]string, rv.Len())
+		for i := range parts {
+			parts[i] = fmt.Sprint(rv.Index(i).Interface())
+		}
+		return strings.Join(parts, ", ")
+	}
+
+	return fmt.Sprint(v)
+}
+
+// pluralCategory implements the CLDR cardinal rules of common languages
+// for integer counts, languages which are not listed follow english
+func pluralCategory(locale string, n int64) string {
+	if n < 0 {
+		n = -n
+	}
+	mod10, mod100 := n%10, n%100
+
+	switch baseLanguage(locale) {
+	case "ja", "zh", "ko", "th", "vi", "id", "ms", "tr":
+		return "other"
+	case "fr", "hi", "bn", "pt":
+		if n == 0 || n == 1 {
+			return "one"
+		}
+	case "ru", "uk", "be":
+		switch {
+		case mod10 == 1 && mod100 != 11:
+			return "one"
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return "few"
+		}
+		return "many"
+	case "pl":
+		switch {
+		case n == 1:
+			return "one"
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return "few"
+		}
+		return "many"
+	case "cs", "sk":
+		switch {
+		case n == 1:
+			return "one"
+		case n >= 2 && n <= 4:
+			return "few"
+		}
+	default:
+		if n == 1 {
+			return "one"
+		}
+	}
+
+	return "other"
+}
+
+// parseAcceptLanguage returns the languages of the header ordered by
+// their quality value
+func parseAcceptLanguage(header string) []string {
+	type lang struct {
+		tag string
+		q   float64
+	}
+
+	var langs []lang
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		q := 1.0
+		for _, f := range fields[1:] {
+			f = strings.TrimSpace(f)
+			if strings.HasPrefix(f, "q=") {
+				if v, err := strconv.ParseFloat(f[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+
+		if q > 0 {
+			langs = append(langs, lang{tag, q})
+		}
+	}
+
+	sort.SliceStable(langs, func(i, j int) bool {
+		return langs[i].q > langs[j].q
+	})
+
+	tags := make([]string, len(langs))
+	for i, l := range langs {
+		tags[i] = l.tag
+	}
+
+	return tags
+}
+
+func localeChain(locale string) []string {
+	locale = normalizeLocale(locale)
+	chain := []string{locale}
+	if base := baseLanguage(locale); base != locale {
+		chain = append(chain, base)
+	}
+
+	if locale != DefaultLocale {
+		chain = append(chain, DefaultLocale)
+	}
+
+	return chain
+}
+
+func hasCatalog(locale string) bool {
+	catalogs.RLock()
+	defer catalogs.RUnlock()
+	_, ok := catalogs.m[normalizeLocale(locale)]
+	return ok
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.Replace(strings.TrimSpace(locale), "_", "-", -1))
+}
+
+func baseLanguage(locale string) string {
+	locale = normalizeLocale(locale)
+	if i := strings.Index(locale, "-"); i > 0 {
+		return locale[:i]
+	}
+
+	return locale
+}
@@ -0,0 +1,147 @@
+package checker
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPluralCategory(t *testing.T) {
+	tests := []struct {
+		locale string
+		counts map[int64]string
+	}{
+		{"en", map[int64]string{0: "other", 1: "one", 2: "other", 11: "other", 21: "other"}},
+		{"en-GB", map[int64]string{1: "one", 5: "other"}},
+		{"fr", map[int64]string{0: "one", 1: "one", 2: "other"}},
+		{"ru", map[int64]string{1: "one", 2: "few", 4: "few", 5: "many", 11: "many",
+			12: "many", 21: "one", 22: "few", 111: "many", 0: "many"}},
+		{"pl", map[int64]string{1: "one", 2: "few", 5: "many", 12: "many", 21: "many",
+			22: "few"}},
+		{"cs", map[int64]string{1: "one", 3: "few", 5: "other"}},
+		{"ja", map[int64]string{1: "other", 2: "other"}},
+		{"xx", map[int64]string{1: "one", -1: "one", 2: "other"}},
+	}
+
+	for _, tt := range tests {
+		for n, want := range tt.counts {
+			if got := pluralCategory(tt.locale, n); got != want {
+				t.Errorf("%s %d: got %s, want %s", tt.locale, n, got, want)
+			}
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	RegisterCatalog("ru", Catalog{
+		"str.min": {Count: "min", Plural: map[string]string{
+			"one":  "{field}: минимум {min} символ",
+			"few":  "{field}: минимум {min} символа",
+			"many": "{field}: минимум {min} символов",
+		}},
+	})
+
+	tests := []struct {
+		locale string
+		min    int
+		want   string
+	}{
+		{"ru", 1, "name: минимум 1 символ"},
+		{"ru-RU", 3, "name: минимум 3 символа"},
+		{"ru", 5, "name: минимум 5 символов"},
+		{"de", 2, "name needs at least 2 characters"},
+		{"en", 1, "name needs at least 1 character"},
+	}
+
+	for _, tt := range tests {
+		err := ValidationErrors{{Field: "name", Code: "str.min",
+			Params: Params{"min": tt.min}, Message: "name is too short"}}
+		got := Localize(err, tt.locale).(ValidationErrors)[0].Message
+		if got != tt.want {
+			t.Errorf("%s %d: got %q, want %q", tt.locale, tt.min, got, tt.want)
+		}
+	}
+
+	untouched := ValidationError{Code: "custom", Message: "$ is odd"}
+	if got := Translate(untouched, "ru"); got.Message != untouched.Message {
+		t.Errorf("a code without a message changed: %q", got.Message)
+	}
+}
+
+func TestLocaleFromRequest(t *testing.T) {
+	RegisterCatalog("pt-BR", Catalog{"required": {Text: "{field} é obrigatório"}})
+	RegisterCatalog("fr", Catalog{"required": {Text: "{field} est obligatoire"}})
+
+	tests := []struct {
+		url    string
+		accept string
+		want   string
+	}{
+		{"/", "", DefaultLocale},
+		{"/", "fr-CA,fr;q=0.8,en;q=0.5", "fr"},
+		{"/", "en;q=0.2, pt_BR;q=0.9", "pt-br"},
+		{"/", "de, *;q=0.1", DefaultLocale},
+		{"/", "fr;q=0", DefaultLocale},
+		{"/?locale=fr", "pt-BR", "fr"},
+		{"/?locale=fr-CA", "pt-BR", "fr"},
+		{"/?locale=fr_ca", "", "fr"},
+		{"/?locale=de", "pt-BR", "pt-br"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.url, nil)
+		req.Header.Set("Accept-Language", tt.accept)
+		if got := LocaleFromRequest(req); got != tt.want {
+			t.Errorf("%s %q: got %s, want %s", tt.url, tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestLoadCatalog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "catalogs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	toml := `required = "{field} ist erforderlich"
+
+[str.min]
+count = "min"
+one = "{field} braucht mindestens {min} Zeichen"
+other = "{field} braucht mindestens {min} Zeichen"
+`
+	if err := ioutil.WriteFile(filepath.Join(dir, "eo.toml"), []byte(toml), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "notes.txt"), []byte("skipped"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := LoadCatalogs(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	catalogs.RLock()
+	eo := catalogs.m["eo"]
+	catalogs.RUnlock()
+	want := Catalog{
+		"required": {Text: "{field} ist erforderlich"},
+		"str.min": {Count: "min", Plural: map[string]string{
+			"one":   "{field} braucht mindestens {min} Zeichen",
+			"other": "{field} braucht mindestens {min} Zeichen",
+		}},
+	}
+	if !reflect.DeepEqual(eo, want) {
+		t.Errorf("got %v, want %v", eo, want)
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	ioutil.WriteFile(bad, []byte(`{"required": 3}`), 0644)
+	if err := LoadCatalog("xx", bad); err == nil {
+		t.Error("a message which is not a string was loaded")
+	}
+}
@@ -0,0 +1,118 @@
+package checker
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	r "github.com/rubikorg/rubik"
+)
+
+// Alpha only allows letters of any script inside the string value of
+// the request field. Combining marks are treated as part of the letter
+// they belong to, so scripts like Devanagari are accepted
+func Alpha(val interface{}) error {
+	return checkRunes(val, "str.alpha", nil, "letters", isLetter)
+}
+
+// AlphaNumeric only allows letters and decimal digits of any script
+// inside the string value of the request field
+func AlphaNumeric(val interface{}) error {
+	return checkRunes(val, "str.alphanum", nil, "letters and digits", func(ru rune) bool {
+		return isLetter(ru) || unicode.IsDigit(ru)
+	})
+}
+
+// ASCIIOnly only allows ASCII characters inside the string value of the
+// request field
+func ASCIIOnly(val interface{}) error {
+	return checkRunes(val, "str.ascii", nil, "ASCII characters", func(ru rune) bool {
+		return ru < utf8.RuneSelf
+	})
+}
+
+// NoControlChars rejects control characters like NUL, escape or line
+// breaks inside the string value of the request field
+func NoControlChars(val interface{}) error {
+	return checkRunes(val, "str.printable", nil, "printable characters", func(ru rune) bool {
+		return !unicode.IsControl(ru)
+	})
+}
+
+// UnicodeCategories only allows characters which belong to one of the
+// given unicode tables, i.e: UnicodeCategories(unicode.L, unicode.Nd)
+func UnicodeCategories(tables ...*unicode.RangeTable) r.Assertion {
+	names := tableNames(tables)
+	what := "characters of " + strings.Join(names, ", ")
+	params := Params{"categories": names}
+	return func(val interface{}) error {
+		return checkRunes(val, "str.categories", params, what, func(ru rune) bool {
+			return unicode.IsOneOf(tables, ru)
+		})
+	}
+}
+
+func isLetter(ru rune) bool {
+	return unicode.IsLetter(ru) || unicode.IsMark(ru)
+}
+
+// checkRunes makes sure every rune of the string value satisfies ok, the
+// error names the first offending character and its position starting
+// from 1 and adds them to the given params
+func checkRunes(val interface{}, code string, params Params, what string,
+	ok func(rune) bool) error {
+	err := IsStr(val)
+	if err != nil || val == nil {
+		return err
+	}
+
+	for pos, ru := range []rune(strValue(val)) {
+		if !ok(ru) {
+			p := Params{"char": string(ru), "position": pos + 1}
+			for k, v := range params {
+				p[k] = v
+			}
+			return errorf(code, p, "$ must only contain %s but found %q at position %d",
+				what, ru, pos+1)
+		}
+	}
+
+	return nil
+}
+
+// tableNames finds the names of unicode tables for readable error
+// messages
+func tableNames(tables []*unicode.RangeTable) []string {
+	var names []string
+	for _, t := range tables {
+		name := "custom table"
+		for _, known := range []map[string]*unicode.RangeTable{
+			unicode.Categories, unicode.Scripts, unicode.Properties} {
+			if n, found := lookupTable(known, t); found {
+				name = n
+				break
+			}
+		}
+		names = append(names, name)
+	}
+
+	return names
+}
+
+func lookupTable(known map[string]*unicode.RangeTable, t *unicode.RangeTable) (string, bool) {
+	var names []string
+	for name, table := range known {
+		if table == t {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return "", false
+	}
+
+	// some tables are registered under multiple names, pick a stable one
+	sort.Strings(names)
+	return names[0], true
+}
@@ -0,0 +1,50 @@
+package checker
+
+import (
+	"testing"
+	"unicode"
+)
+
+func TestCharsets(t *testing.T) {
+	tests := []struct {
+		name     string
+		assert   func(interface{}) error
+		val      string
+		code     string
+		char     string
+		position int
+	}{
+		{"allow", StrAllow('a', 'b', 'é'), "abéba", "", "", 0},
+		{"allow after multi-byte", StrAllow('a', 'b', 'é'), "éé€a", "str.allow", "€", 3},
+		{"deny", StrDeny('<', '>'), "plain text", "", "", 0},
+		{"deny after multi-byte", StrDeny('<', '>'), "日本<語", "str.deny", "<", 3},
+		{"deny multi-byte", StrDeny('€'), "a€", "str.deny", "€", 2},
+		{"alpha devanagari", Alpha, "नमस्ते", "", "", 0},
+		{"alpha digit", Alpha, "ñandú1", "str.alpha", "1", 6},
+		{"alphanumeric", AlphaNumeric, "abc١٢٣", "", "", 0},
+		{"alphanumeric space", AlphaNumeric, "ab c", "str.alphanum", " ", 3},
+		{"ascii", ASCIIOnly, "naïve", "str.ascii", "ï", 3},
+		{"control chars", NoControlChars, "a\x1bb", "str.printable", "\x1b", 2},
+		{"categories", UnicodeCategories(unicode.Greek), "αβγ", "", "", 0},
+		{"categories latin", UnicodeCategories(unicode.Greek), "αβc", "str.categories", "c", 3},
+	}
+
+	for _, tt := range tests {
+		err := tt.assert(tt.val)
+		expectCode(t, tt.name, err, tt.code)
+		if tt.code == "" {
+			continue
+		}
+
+		params := err.(ValidationError).Params
+		if params["char"] != tt.char || params["position"] != tt.position {
+			t.Errorf("%s: got %q at %v, want %q at %d", tt.name, params["char"],
+				params["position"], tt.char, tt.position)
+		}
+	}
+
+	params := UnicodeCategories(unicode.Greek)("c").(ValidationError).Params
+	if names := params["categories"].([]string); len(names) != 1 || names[0] != "Greek" {
+		t.Errorf("categories: got %v, want [Greek]", names)
+	}
+}
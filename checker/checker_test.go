@@ -0,0 +1,28 @@
+package checker
+
+import "testing"
+
+// codeOf returns the rule code of err, an empty string for nil and the
+// message for errors which are not a ValidationError
+func codeOf(err error) string {
+	switch e := err.(type) {
+	case nil:
+		return ""
+	case ValidationError:
+		return e.Code
+	case ValidationErrors:
+		if len(e) > 0 {
+			return e[0].Code
+		}
+		return ""
+	}
+
+	return err.Error()
+}
+
+func expectCode(t *testing.T, name string, err error, code string) {
+	t.Helper()
+	if got := codeOf(err); got != code {
+		t.Errorf("%s: got code %q (%v), want %q", name, got, err, code)
+	}
+}
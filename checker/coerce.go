@@ -0,0 +1,186 @@
+package checker
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// TrueStrings and FalseStrings are the spellings AsBool accepts for
+// strings, they are compared ignoring case and surrounding spaces. Form
+// values like the "on" of a checkbox are accepted by default
+var (
+	TrueStrings  = []string{"true", "yes", "y", "on", "1"}
+	FalseStrings = []string{"false", "no", "n", "off", "0"}
+)
+
+var (
+	errNotBool   = errorf("bool.type", nil, "$ is not a boolean")
+	errNotString = errorf("str.type", nil, "$ cannot be asserted as string")
+)
+
+// AsBool coerces Go booleans, the numbers 1 and 0 and the strings of
+// TrueStrings and FalseStrings into a bool
+func AsBool(val interface{}) (bool, error) {
+	if b, ok := val.(bool); ok {
+		return b, nil
+	}
+
+	if val == nil {
+		return false, errNotBool
+	}
+
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.String:
+		s := strings.TrimSpace(rv.String())
+		for _, t := range TrueStrings {
+			if strings.EqualFold(s, t) {
+				return true, nil
+			}
+		}
+		for _, f := range FalseStrings {
+			if strings.EqualFold(s, f) {
+				return false, nil
+			}
+		}
+		return false, errNotBool
+	}
+
+	i, err := AsInt(val)
+	if err != nil || (i != 0 && i != 1) {
+		return false, errNotBool
+	}
+
+	return i == 1, nil
+}
+
+// AsString returns the text of strings, named string types, json.Number
+// and byte slices. Numbers and booleans are not turned into strings so
+// that a string field sent as a number is still reported
+func AsString(val interface{}) (string, error) {
+	switch v := val.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case nil:
+		return "", errNotString
+	}
+
+	rv := reflect.ValueOf(val)
+	if rv.Kind() == reflect.String {
+		return rv.String(), nil
+	}
+
+	return "", errNotString
+}
+
+// AsInt coerces Go integers, integral floats, json.Number and numeric
+// strings into an int64 and reports overflow and fractions as errors
+func AsInt(val interface{}) (int64, error) {
+	switch v := val.(type) {
+	case json.Number:
+		return parseInt(string(v))
+	case string:
+		return parseInt(v)
+	}
+
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		u := rv.Uint()
+		if u > math.MaxInt64 {
+			return 0, errOverflow
+		}
+		return int64(u), nil
+	case reflect.Float32, reflect.Float64:
+		return floatToInt64(rv.Float())
+	case reflect.String:
+		return parseInt(rv.String())
+	}
+
+	return 0, errNotNumber
+}
+
+func parseInt(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err == nil {
+		return i, nil
+	}
+
+	if ne, ok := err.(*strconv.NumError); ok && ne.Err == strconv.ErrRange {
+		return 0, errOverflow
+	}
+
+	f, ferr := strconv.ParseFloat(s, 64)
+	if ferr != nil {
+		return 0, errNotNumber
+	}
+
+	return floatToInt64(f)
+}
+
+func floatToInt64(f float64) (int64, error) {
+	if math.IsNaN(f) {
+		return 0, errNotNumber
+	}
+
+	if f != math.Trunc(f) {
+		return 0, errNotInteger
+	}
+
+	// float64(math.MaxInt64) rounds up to 2^63, which itself overflows
+	if f >= math.MaxInt64 || f < math.MinInt64 {
+		return 0, errOverflow
+	}
+
+	return int64(f), nil
+}
+
+// AsFloat coerces Go numbers, json.Number and numeric strings into a
+// float64
+func AsFloat(val interface{}) (float64, error) {
+	switch v := val.(type) {
+	case json.Number:
+		return parseFloat(string(v))
+	case string:
+		return parseFloat(v)
+	}
+
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return 0, errNotNumber
+		}
+		return f, nil
+	case reflect.String:
+		return parseFloat(rv.String())
+	}
+
+	return 0, errNotNumber
+}
+
+func parseFloat(s string) (float64, error) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, errNotNumber
+	}
+
+	return f, nil
+}
@@ -0,0 +1,154 @@
+package checker
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+type color string
+
+func TestAsBool(t *testing.T) {
+	type flag bool
+	tests := []struct {
+		val  interface{}
+		want bool
+		code string
+	}{
+		{true, true, ""},
+		{flag(false), false, ""},
+		{"on", true, ""},
+		{" YES ", true, ""},
+		{"off", false, ""},
+		{"0", false, ""},
+		{1, true, ""},
+		{0.0, false, ""},
+		{json.Number("1"), true, ""},
+		{2, false, "bool.type"},
+		{"maybe", false, "bool.type"},
+		{nil, false, "bool.type"},
+		{[]bool{true}, false, "bool.type"},
+	}
+
+	for _, tt := range tests {
+		got, err := AsBool(tt.val)
+		expectCode(t, "AsBool", err, tt.code)
+		if err == nil && got != tt.want {
+			t.Errorf("AsBool(%#v) = %v, want %v", tt.val, got, tt.want)
+		}
+	}
+}
+
+func TestAsBoolSpellings(t *testing.T) {
+	prev := TrueStrings
+	defer func() { TrueStrings = prev }()
+
+	TrueStrings = []string{"ja"}
+	if b, err := AsBool("Ja"); err != nil || !b {
+		t.Errorf("configured spelling: got %v, %v", b, err)
+	}
+	expectCode(t, "removed spelling", func() error { _, err := AsBool("yes"); return err }(),
+		"bool.type")
+}
+
+func TestAsInt(t *testing.T) {
+	tests := []struct {
+		val  interface{}
+		want int64
+		code string
+	}{
+		{42, 42, ""},
+		{uint8(7), 7, ""},
+		{3.0, 3, ""},
+		{json.Number("-12"), -12, ""},
+		{" 12 ", 12, ""},
+		{"1e3", 1000, ""},
+		{color("5"), 5, ""},
+		{3.5, 0, "num.integer"},
+		{"3.5", 0, "num.integer"},
+		{uint64(math.MaxUint64), 0, "num.overflow"},
+		{"9223372036854775808", 0, "num.overflow"},
+		{math.Pow(2, 63), 0, "num.overflow"},
+		{"twelve", 0, "num.type"},
+		{true, 0, "num.type"},
+		{nil, 0, "num.type"},
+	}
+
+	for _, tt := range tests {
+		got, err := AsInt(tt.val)
+		expectCode(t, "AsInt", err, tt.code)
+		if err == nil && got != tt.want {
+			t.Errorf("AsInt(%#v) = %d, want %d", tt.val, got, tt.want)
+		}
+	}
+}
+
+func TestAsFloat(t *testing.T) {
+	tests := []struct {
+		val  interface{}
+		want float64
+		code string
+	}{
+		{1.5, 1.5, ""},
+		{float32(0.5), 0.5, ""},
+		{-3, -3, ""},
+		{json.Number("2.25"), 2.25, ""},
+		{"1e-2", 0.01, ""},
+		{"NaN", 0, "num.type"},
+		{math.Inf(1), 0, "num.type"},
+		{"", 0, "num.type"},
+		{false, 0, "num.type"},
+	}
+
+	for _, tt := range tests {
+		got, err := AsFloat(tt.val)
+		expectCode(t, "AsFloat", err, tt.code)
+		if err == nil && got != tt.want {
+			t.Errorf("AsFloat(%#v) = %g, want %g", tt.val, got, tt.want)
+		}
+	}
+}
+
+func TestAsString(t *testing.T) {
+	tests := []struct {
+		val  interface{}
+		want string
+		code string
+	}{
+		{"abc", "abc", ""},
+		{color("red"), "red", ""},
+		{[]byte("raw"), "raw", ""},
+		{json.Number("12"), "12", ""},
+		{12, "", "str.type"},
+		{true, "", "str.type"},
+		{nil, "", "str.type"},
+	}
+
+	for _, tt := range tests {
+		got, err := AsString(tt.val)
+		expectCode(t, "AsString", err, tt.code)
+		if err == nil && got != tt.want {
+			t.Errorf("AsString(%#v) = %q, want %q", tt.val, got, tt.want)
+		}
+	}
+}
+
+func TestStrIsOneOf(t *testing.T) {
+	check := StrIsOneOf("red", "green")
+	tests := []struct {
+		name string
+		val  interface{}
+		code string
+	}{
+		{"string", "red", ""},
+		{"named string", color("green"), ""},
+		{"bytes", []byte("red"), ""},
+		{"other string", "blue", "str.oneof"},
+		{"number", 1, "str.oneof"},
+		{"nil", nil, "str.oneof"},
+	}
+
+	for _, tt := range tests {
+		expectCode(t, tt.name, check(tt.val), tt.code)
+	}
+}
@@ -0,0 +1,128 @@
+package checker
+
+import (
+	"reflect"
+	"strings"
+
+	r "github.com/rubikorg/rubik"
+)
+
+// All passes only if every given assertion passes. All of them are run
+// so that the error lists every rule the value broke
+func All(assertions ...r.Assertion) r.Assertion {
+	return func(val interface{}) error {
+		var errs ValidationErrors
+		for _, assert := range assertions {
+			errs = append(errs, bindField("", assert(val))...)
+		}
+
+		if len(errs) == 0 {
+			return nil
+		}
+
+		if len(errs) == 1 {
+			return errs[0]
+		}
+
+		return errs
+	}
+}
+
+// Any passes if at least one of the given assertions passes, if none of
+// them pass the error has the `any` code and lists the errors of each
+// one of them under the `alternatives` parameter
+func Any(assertions ...r.Assertion) r.Assertion {
+	return func(val interface{}) error {
+		// only one of the alternatives has to hold, none is described
+		if describes(val) {
+			return nil
+		}
+
+		var errs ValidationErrors
+		for _, assert := range assertions {
+			err := assert(val)
+			if err == nil {
+				return nil
+			}
+			errs = append(errs, bindField("", err)...)
+		}
+
+		if len(errs) == 0 {
+			return nil
+		}
+
+		if len(errs) == 1 {
+			return errs[0]
+		}
+
+		msgs := make([]string, len(errs))
+		for i, e := range errs {
+			msgs[i] = e.Message
+		}
+
+		return ValidationError{
+			Code:    "any",
+			Params:  Params{"alternatives": []ValidationError(errs)},
+			Message: strings.Join(msgs, " or "),
+		}
+	}
+}
+
+// Not inverts the given assertion, the value is rejected when the
+// assertion passes
+func Not(assertion r.Assertion) r.Assertion {
+	return func(val interface{}) error {
+		if describes(val) {
+			return nil
+		}
+
+		if assertion(val) == nil {
+			return errorf("not", nil, "$ is not allowed")
+		}
+
+		return nil
+	}
+}
+
+// Optional skips the given assertions when the value is nil or empty and
+// runs them like All otherwise
+func Optional(assertions ...r.Assertion) r.Assertion {
+	all := All(assertions...)
+	return func(val interface{}) error {
+		if isEmpty(val) {
+			return nil
+		}
+
+		return all(val)
+	}
+}
+
+// When runs the assertion only if the predicate returns true for the
+// value of the request field
+func When(predicate func(interface{}) bool, assertion r.Assertion) r.Assertion {
+	return func(val interface{}) error {
+		if describes(val) || !predicate(val) {
+			return nil
+		}
+
+		return assertion(val)
+	}
+}
+
+// isEmpty reports if the value is nil, a nil pointer or has a length of
+// zero
+func isEmpty(val interface{}) bool {
+	if val == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array, reflect.Chan:
+		return rv.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return rv.IsNil()
+	}
+
+	return false
+}
@@ -0,0 +1,62 @@
+package checker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCombinators(t *testing.T) {
+	short := All(StrMin(5), StrIsOneOf("long enough"))
+	tests := []struct {
+		name  string
+		err   error
+		codes []string
+	}{
+		{"all passes", All(IsStr, StrMin(1))("a"), nil},
+		{"all reports every rule", short("abc"), []string{"str.min", "str.oneof"}},
+		{"all with one failure", All(IsStr, StrMin(5))("abc"), []string{"str.min"}},
+		{"any passes", Any(IsUUID, IsEmail)("john@example.com"), nil},
+		{"any fails", Any(IsUUID, IsEmail)("john"), []string{"any"}},
+		{"any with one alternative", Any(IsUUID)("john"), []string{"format.uuid"}},
+		{"not", Not(IsEmail)("john@example.com"), []string{"not"}},
+		{"optional nil", Optional(IsEmail)(nil), nil},
+		{"optional empty", Optional(IsEmail)(""), nil},
+		{"optional empty list", Optional(SliceMin(1))([]string{}), nil},
+		{"optional value", Optional(short)("abc"), []string{"str.min", "str.oneof"}},
+		{"when skipped", When(func(interface{}) bool { return false }, IsEmail)("john"), nil},
+		{"when run", When(func(interface{}) bool { return true }, IsEmail)("john"), []string{"email"}},
+	}
+
+	for _, tt := range tests {
+		var codes []string
+		switch e := tt.err.(type) {
+		case ValidationErrors:
+			for _, ve := range e {
+				codes = append(codes, ve.Code)
+			}
+		case ValidationError:
+			codes = []string{e.Code}
+		}
+
+		if !reflect.DeepEqual(codes, tt.codes) {
+			t.Errorf("%s: got codes %v (%v), want %v", tt.name, codes, tt.err, tt.codes)
+		}
+	}
+}
+
+func TestAnyMessage(t *testing.T) {
+	err := Any(IsUUID, IsEmail)("john").(ValidationError)
+	if want := "$ must be a valid UUID or $ is not a valid email address"; err.Message != want {
+		t.Errorf("got %q, want %q", err.Message, want)
+	}
+
+	alternatives := err.Params["alternatives"].([]ValidationError)
+	if len(alternatives) != 2 || alternatives[1].Code != "email" {
+		t.Errorf("got alternatives %v", alternatives)
+	}
+
+	bound := bindField("contact", err)[0]
+	if want := "contact must be a valid UUID or contact is not a valid email address"; bound.Error() != want {
+		t.Errorf("got %q, want %q", bound.Error(), want)
+	}
+}
@@ -0,0 +1,185 @@
+package checker
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	r "github.com/rubikorg/rubik"
+)
+
+// BlockName is the name of this block
+const BlockName = "Checker"
+
+// ConfigKey is the table of the rubik config holding the schemas, i.e:
+//
+//	[validation.signup]
+//	email = ["required", "email"]
+//	username = "required,min=3,max=20"
+//	"address.zip" = ["min=5", "max=5"]
+//
+// Each table is a schema named after its key, each field takes a list of
+// named rules or a comma separated string like the `check` tag
+const ConfigKey = "validation"
+
+// BlockChecker resolves the schemas of the rubik config into assertions
+// when the app starts, unknown rule names stop the app from starting
+type BlockChecker struct{}
+
+// configSchemas holds the schemas read from the config and the names
+// asked for by ConfigSchema before the block was attached
+var configSchemas = struct {
+	sync.RWMutex
+	m      map[string]Schema
+	wanted map[string]bool
+}{m: make(map[string]Schema), wanted: make(map[string]bool)}
+
+// RegisterAssertion makes an assertion without parameters available as a
+// named rule to the config and to the `check` tag, the given rules
+// describe it in the docs, i.e:
+// RegisterAssertion("slug", isSlug, Rule{"pattern", "^[a-z0-9-]+$"})
+func RegisterAssertion(name string, assert r.Assertion, rules ...Rule) error {
+	if assert == nil {
+		return fmt.Errorf("checker: rule %s has no assertion", name)
+	}
+
+	if len(rules) > 0 {
+		assert = Describe(assert, rules...)
+	}
+
+	return RegisterRule(name, noParam(name, assert))
+}
+
+// ConfigSchema returns the middleware validating the entity of a route
+// against the schema of the config with the given name. It can be used
+// while declaring routes, the schema is resolved once the block is
+// attached and a name missing from the config stops the app from
+// starting
+func ConfigSchema(name string) r.Controller {
+	configSchemas.Lock()
+	configSchemas.wanted[name] = true
+	configSchemas.Unlock()
+
+	return func(req *r.Request) {
+		schema, ok := LookupConfigSchema(name)
+		if !ok {
+			err := fmt.Errorf("checker: schema %s is not configured", name)
+			req.Throw(http.StatusInternalServerError, err, r.Type.JSON)
+			return
+		}
+
+		schema.Middleware(req)
+	}
+}
+
+// LookupConfigSchema returns the schema read from the config with the
+// given name
+func LookupConfigSchema(name string) (Schema, bool) {
+	configSchemas.RLock()
+	defer configSchemas.RUnlock()
+	schema, ok := configSchemas.m[name]
+	return schema, ok
+}
+
+// OnAttach implementation for checker block
+func (BlockChecker) OnAttach(app *r.App) error {
+	return attachConfig(app.Config(ConfigKey))
+}
+
+// attachConfig stores the schemas of the validation table and makes sure
+// every schema asked for by ConfigSchema is among them
+func attachConfig(conf interface{}) error {
+	schemas, err := parseConfigSchemas(conf)
+	if err != nil {
+		return err
+	}
+
+	configSchemas.Lock()
+	defer configSchemas.Unlock()
+	for name, schema := range schemas {
+		configSchemas.m[name] = schema
+	}
+
+	var missing []string
+	for name := range configSchemas.wanted {
+		if _, ok := configSchemas.m[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("checker: [%s.%s] is missing from the config",
+			ConfigKey, strings.Join(missing, "], ["+ConfigKey+"."))
+	}
+
+	return nil
+}
+
+// parseConfigSchemas turns the validation table of the config into
+// schemas, all errors of the table are reported together
+func parseConfigSchemas(conf interface{}) (map[string]Schema, error) {
+	schemas := make(map[string]Schema)
+	if conf == nil {
+		return schemas, nil
+	}
+
+	tables, ok := conf.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("checker: [%s] must be a table", ConfigKey)
+	}
+
+	var problems []string
+	for name, t := range tables {
+		fields, ok := t.(map[string]interface{})
+		if !ok {
+			problems = append(problems, fmt.Sprintf("[%s.%s] must be a table", ConfigKey, name))
+			continue
+		}
+
+		schema := Schema{Fields: make(map[string][]r.Assertion, len(fields))}
+		for field, spec := range fields {
+			assertions, err := parseConfigRules(spec)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s.%s.%s: %v", ConfigKey, name, field, err))
+				continue
+			}
+			schema.Fields[field] = assertions
+		}
+		schemas[name] = schema
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, fmt.Errorf("checker: %s", strings.Join(problems, "; "))
+	}
+
+	return schemas, nil
+}
+
+func parseConfigRules(spec interface{}) ([]r.Assertion, error) {
+	switch s := spec.(type) {
+	case string:
+		return ParseRules(s)
+	case []interface{}:
+		rules := make([]string, len(s))
+		for i, item := range s {
+			rule, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("rules must be strings but got %v", item)
+			}
+			rules[i] = rule
+		}
+		return ParseRules(strings.Join(rules, ","))
+	case []string:
+		return ParseRules(strings.Join(s, ","))
+	}
+
+	return nil, fmt.Errorf("rules must be a list or a string but got %v", spec)
+}
+
+func init() {
+	r.Attach(BlockName, BlockChecker{})
+}
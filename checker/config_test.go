@@ -0,0 +1,90 @@
+package checker
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// resetConfigSchemas forgets the schemas of the config and the names
+// asked for by ConfigSchema
+func resetConfigSchemas() {
+	configSchemas.Lock()
+	configSchemas.m = make(map[string]Schema)
+	configSchemas.wanted = make(map[string]bool)
+	configSchemas.Unlock()
+}
+
+func TestAttachConfig(t *testing.T) {
+	defer resetConfigSchemas()
+	tests := []struct {
+		name    string
+		conf    interface{}
+		wanted  []string
+		problem string
+	}{
+		{"no table", nil, nil, ""},
+		{"schemas", map[string]interface{}{
+			"signup": map[string]interface{}{"email": "required,email"},
+		}, []string{"signup"}, ""},
+		{"unknown rule", map[string]interface{}{
+			"signup": map[string]interface{}{"email": []interface{}{"required", "emial"}},
+		}, nil, `validation.signup.email: unknown rule "emial"`},
+		{"bad parameter", map[string]interface{}{
+			"signup": map[string]interface{}{"name": "min=three"},
+		}, nil, "min needs an integer parameter"},
+		{"not a table", map[string]interface{}{"signup": "required"}, nil,
+			"[validation.signup] must be a table"},
+		{"missing schema", map[string]interface{}{
+			"signup": map[string]interface{}{"email": "email"},
+		}, []string{"signup", "login", "reset"},
+			"[validation.login], [validation.reset] is missing from the config"},
+	}
+
+	for _, tt := range tests {
+		resetConfigSchemas()
+		for _, name := range tt.wanted {
+			ConfigSchema(name)
+		}
+
+		err := attachConfig(tt.conf)
+		switch {
+		case tt.problem == "" && err != nil:
+			t.Errorf("%s: %v", tt.name, err)
+		case tt.problem != "" && (err == nil || !strings.Contains(err.Error(), tt.problem)):
+			t.Errorf("%s: got %v, want an error about %s", tt.name, err, tt.problem)
+		}
+	}
+
+	if _, ok := LookupConfigSchema("signup"); !ok {
+		t.Error("the schemas of the config were not stored")
+	}
+}
+
+func TestConfigRuleForms(t *testing.T) {
+	schemas, err := parseConfigSchemas(map[string]interface{}{
+		"list":   map[string]interface{}{"username": []interface{}{"required", "min=3", "max=20"}},
+		"string": map[string]interface{}{"username": "required, min=3,max=20"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list, str := schemas["list"], schemas["string"]
+	listDocs, strDocs := DescribeFields(nil, list.Fields), DescribeFields(nil, str.Fields)
+	if !reflect.DeepEqual(listDocs, strDocs) {
+		t.Errorf("got docs %v and %v, want the same rules", listDocs, strDocs)
+	}
+
+	for _, val := range []interface{}{"", "an", "ann", strings.Repeat("a", 21)} {
+		entity := map[string]interface{}{"username": val}
+		a, b := codeOf(list.Validate(entity)), codeOf(str.Validate(entity))
+		if a != b {
+			t.Errorf("%q: got %q for the list and %q for the string", val, a, b)
+		}
+	}
+
+	if _, err := parseConfigRules([]interface{}{"required", 3}); err == nil {
+		t.Error("a rule which is not a string was parsed")
+	}
+}
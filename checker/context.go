@@ -0,0 +1,207 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ContextAssertion is an assertion which needs I/O like a database
+// lookup. It runs after the plain assertions of its field passed and
+// should give up when ctx is done
+type ContextAssertion func(ctx context.Context, val interface{}) error
+
+// LookupFunc reports if val is already known, i.e: if a user with the
+// given name exists
+type LookupFunc func(ctx context.Context, val interface{}) (bool, error)
+
+type cacheKey struct {
+	lookup *int
+	val    string
+}
+
+// lookupEntry is a lookup which may still be running, done is closed
+// once found and err are set
+type lookupEntry struct {
+	done  chan struct{}
+	found bool
+	err   error
+}
+
+// lookupCache keeps the results of the lookups made while validating a
+// single request, fields asking for the same lookup at the same time
+// wait for the first one
+type lookupCache struct {
+	sync.Mutex
+	m map[cacheKey]*lookupEntry
+}
+
+type lookupCacheKey struct{}
+
+// WithLookupCache returns a context in which Unique and Exists remember
+// the results of their lookups. ValidateContext adds one when ctx has
+// none, share a context between schemas to share their lookups too
+func WithLookupCache(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(lookupCacheKey{}).(*lookupCache); ok {
+		return ctx
+	}
+
+	return context.WithValue(ctx, lookupCacheKey{}, &lookupCache{m: make(map[cacheKey]*lookupEntry)})
+}
+
+// Unique checks that the value is not known to lookup, i.e: that a user
+// name is not taken yet
+func Unique(lookup LookupFunc) ContextAssertion {
+	id := new(int)
+	return func(ctx context.Context, val interface{}) error {
+		if val == nil {
+			return nil
+		}
+
+		found, err := cachedLookup(ctx, id, lookup, val)
+		if err != nil {
+			return err
+		}
+
+		if found {
+			return errorf("unique", nil, "$ is already taken")
+		}
+
+		return nil
+	}
+}
+
+// Exists checks that the value is known to lookup, i.e: that a coupon
+// code was issued
+func Exists(lookup LookupFunc) ContextAssertion {
+	id := new(int)
+	return func(ctx context.Context, val interface{}) error {
+		if val == nil {
+			return nil
+		}
+
+		found, err := cachedLookup(ctx, id, lookup, val)
+		if err != nil {
+			return err
+		}
+
+		if !found {
+			return errorf("exists", nil, "$ does not exist")
+		}
+
+		return nil
+	}
+}
+
+// WithTimeout gives the assertion at most d to finish, a slower
+// assertion is reported with the code timeout
+func WithTimeout(d time.Duration, assert ContextAssertion) ContextAssertion {
+	return func(ctx context.Context, val interface{}) error {
+		ctx, cancel := context.WithTimeout(ctx, d)
+		defer cancel()
+
+		done := make(chan error, 1)
+		go func() {
+			done <- assert(ctx, val)
+		}()
+
+		select {
+		case err := <-done:
+			return err
+		case <-ctx.Done():
+			return errorf("timeout", Params{"timeout": d.String()},
+				"$ could not be checked in time")
+		}
+	}
+}
+
+// ValidateContext is like Validate but also runs the context assertions
+// of the schema. The context assertions of different fields run
+// concurrently and lookups are cached for the whole call
+func (s Schema) ValidateContext(ctx context.Context, entity interface{}) error {
+	return s.validate(WithLookupCache(ctx), entity)
+}
+
+// asyncField is a field whose context assertions are still to run
+type asyncField struct {
+	name string
+	val  interface{}
+}
+
+// runAsync runs the context assertions of every field in its own
+// goroutine, the errors keep the order of fields
+func (s Schema) runAsync(ctx context.Context, fields []asyncField) ValidationErrors {
+	results := make([]ValidationErrors, len(fields))
+	var wg sync.WaitGroup
+	for i, f := range fields {
+		wg.Add(1)
+		go func(i int, f asyncField) {
+			defer wg.Done()
+			for _, assert := range s.Async[f.name] {
+				if err := assert(ctx, f.val); err != nil {
+					results[i] = bindField(f.name, err)
+					return
+				}
+			}
+		}(i, f)
+	}
+	wg.Wait()
+
+	var errs ValidationErrors
+	for _, res := range results {
+		errs = append(errs, res...)
+	}
+
+	return errs
+}
+
+func cachedLookup(ctx context.Context, id *int, lookup LookupFunc,
+	val interface{}) (bool, error) {
+	cache, _ := ctx.Value(lookupCacheKey{}).(*lookupCache)
+	if cache == nil {
+		return runLookup(ctx, lookup, val)
+	}
+
+	key := cacheKey{lookup: id, val: fmt.Sprintf("%T %v", val, val)}
+	cache.Lock()
+	entry, ok := cache.m[key]
+	if !ok {
+		entry = &lookupEntry{done: make(chan struct{})}
+		cache.m[key] = entry
+	}
+	cache.Unlock()
+
+	if ok {
+		select {
+		case <-entry.done:
+			return entry.found, entry.err
+		case <-ctx.Done():
+			return false, errorf("timeout", nil, "$ could not be checked in time")
+		}
+	}
+
+	entry.found, entry.err = runLookup(ctx, lookup, val)
+	if entry.err != nil {
+		// failed lookups are not kept so that a later field can retry
+		cache.Lock()
+		delete(cache.m, key)
+		cache.Unlock()
+	}
+	close(entry.done)
+
+	return entry.found, entry.err
+}
+
+func runLookup(ctx context.Context, lookup LookupFunc, val interface{}) (bool, error) {
+	found, err := lookup(ctx, val)
+	if err == nil {
+		return found, nil
+	}
+
+	if ctx.Err() != nil {
+		return false, errorf("timeout", nil, "$ could not be checked in time")
+	}
+
+	return false, errorf("lookup", nil, "$ could not be checked right now")
+}
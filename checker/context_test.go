@@ -0,0 +1,107 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	r "github.com/rubikorg/rubik"
+)
+
+// countingLookup finds the values of taken after waiting for delay and
+// counts its calls
+func countingLookup(calls *int32, delay time.Duration, taken ...string) LookupFunc {
+	return func(ctx context.Context, val interface{}) (bool, error) {
+		atomic.AddInt32(calls, 1)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+
+		for _, v := range taken {
+			if v == val {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
+func TestSharedLookup(t *testing.T) {
+	var calls int32
+	unique := Unique(countingLookup(&calls, 20*time.Millisecond, "ann@example.com"))
+	schema := Schema{
+		Fields: map[string][]r.Assertion{"email": {IsEmail}},
+		Async: map[string][]ContextAssertion{
+			"email":        {unique},
+			"backup_email": {unique},
+		},
+	}
+
+	err := schema.ValidateContext(context.Background(), map[string]interface{}{
+		"email":        "ann@example.com",
+		"backup_email": "ann@example.com",
+	})
+	errs, _ := err.(ValidationErrors)
+	if len(errs) != 2 || errs[0].Field != "backup_email" || errs[1].Field != "email" {
+		t.Fatalf("got %v, want both fields taken", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("got %d lookups, want 1", got)
+	}
+
+	ctx := WithLookupCache(context.Background())
+	for i := 0; i < 2; i++ {
+		schema.ValidateContext(ctx, map[string]interface{}{"email": "bob@example.com"})
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("got %d lookups with a shared cache, want 2", got)
+	}
+
+	schema.Validate(map[string]interface{}{"email": "not an email"})
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("got %d lookups, a lookup ran after the plain assertions failed", got)
+	}
+}
+
+func TestFailedLookupRetried(t *testing.T) {
+	var calls int32
+	exists := Exists(func(ctx context.Context, val interface{}) (bool, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return false, errors.New("connection refused")
+		}
+		return val == "SUMMER", nil
+	})
+
+	ctx := WithLookupCache(context.Background())
+	expectCode(t, "failed", exists(ctx, "SUMMER"), "lookup")
+	expectCode(t, "retried", exists(ctx, "SUMMER"), "")
+	expectCode(t, "cached", exists(ctx, "SUMMER"), "")
+	expectCode(t, "unknown", exists(ctx, "WINTER"), "exists")
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("got %d lookups, want 3", got)
+	}
+}
+
+func TestLookupTimeout(t *testing.T) {
+	var calls int32
+	slow := Unique(countingLookup(&calls, time.Second))
+
+	err := WithTimeout(10*time.Millisecond, slow)(context.Background(), "ann")
+	expectCode(t, "with timeout", err, "timeout")
+	if d := err.(ValidationError).Params["timeout"]; d != "10ms" {
+		t.Errorf("got timeout param %v, want 10ms", d)
+	}
+
+	schema := Schema{Async: map[string][]ContextAssertion{"name": {slow}}}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	expectCode(t, "request deadline", schema.ValidateContext(ctx, map[string]interface{}{
+		"name": "ann"}), "timeout")
+
+	fast := WithTimeout(time.Second, Unique(countingLookup(&calls, 0, "ann")))
+	expectCode(t, "in time", fast(context.Background(), "ann"), "unique")
+}
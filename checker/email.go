@@ -0,0 +1,169 @@
+package checker
+
+import (
+	"net/mail"
+	"strings"
+
+	r "github.com/rubikorg/rubik"
+)
+
+// EmailOptions is the policy used by EmailWith to decide if the value
+// of a request field is an acceptable email address
+type EmailOptions struct {
+	// AllowDisplayName accepts the `John Doe <john@example.com>` form
+	AllowDisplayName bool
+	// AllowedDomains when not empty only accepts addresses of these
+	// domains and their subdomains
+	AllowedDomains []string
+	// DeniedDomains rejects addresses of these domains and their
+	// subdomains, i.e: disposable email providers
+	DeniedDomains []string
+	// DisallowPlus rejects plus addressing like `john+promo@example.com`
+	DisallowPlus bool
+	// DisallowIDN rejects internationalized domain names, by default
+	// they are accepted and compared in their punycode form
+	DisallowIDN bool
+}
+
+// defaultEmailOptions is the policy of IsEmail
+var defaultEmailOptions = EmailOptions{}
+
+const (
+	maxEmailLocalLen = 64
+	maxDomainLen     = 253
+	maxLabelLen      = 63
+)
+
+// IsEmail checks if given field is an email or not. Display names are not
+// allowed and any valid domain is accepted, use EmailWith if you need a
+// different policy
+func IsEmail(val interface{}) error {
+	return checkEmail(val, defaultEmailOptions, nil, nil)
+}
+
+// EmailWith creates an email assertion which follows the given policy.
+// The domain lists are normalized once when the assertion is created
+func EmailWith(opts EmailOptions) r.Assertion {
+	allowed := normalizeDomains(opts.AllowedDomains)
+	denied := normalizeDomains(opts.DeniedDomains)
+	return func(val interface{}) error {
+		return checkEmail(val, opts, allowed, denied)
+	}
+}
+
+func checkEmail(val interface{}, opts EmailOptions, allowed, denied []string) error {
+	if describes(val, Rule{"format", "email"}) {
+		return nil
+	}
+
+	err := IsStr(val)
+	if err != nil {
+		return err
+	}
+
+	if val == nil {
+		return nil
+	}
+
+	raw := strings.TrimSpace(strValue(val))
+	addr, err := mail.ParseAddress(raw)
+	if err != nil {
+		return errorf("email", nil, "$ is not a valid email address")
+	}
+
+	if !opts.AllowDisplayName && (addr.Name != "" || strings.ContainsAny(raw, "<>")) {
+		return errorf("email.display_name", nil,
+			"$ must be a plain email address without a display name")
+	}
+
+	at := strings.LastIndex(addr.Address, "@")
+	if at <= 0 {
+		return errorf("email", nil, "$ is not a valid email address")
+	}
+	local, domain := addr.Address[:at], addr.Address[at+1:]
+
+	if len(local) > maxEmailLocalLen {
+		return errorf("email.local_length", Params{"max": maxEmailLocalLen},
+			"$ must not have more than %d characters before @", maxEmailLocalLen)
+	}
+
+	if opts.DisallowPlus && strings.Contains(local, "+") {
+		return errorf("email.plus", nil, "$ must not use plus addressing")
+	}
+
+	if !isASCII(domain) && opts.DisallowIDN {
+		return errorf("email.idn", nil, "$ must not use an internationalized domain")
+	}
+
+	asciiDomain, err := toASCII(domain)
+	if err != nil || !isHostname(asciiDomain) || !strings.Contains(asciiDomain, ".") {
+		return errorf("email.domain", nil, "$ does not have a valid email domain")
+	}
+
+	notAllowed := len(allowed) > 0 && !matchesDomain(asciiDomain, allowed)
+	if notAllowed || matchesDomain(asciiDomain, denied) {
+		return errorf("email.domain_denied", Params{"domain": domain},
+			"$ domain %s is not allowed", domain)
+	}
+
+	return nil
+}
+
+// isHostname checks if the ASCII form of a host follows RFC 1123 label
+// rules
+func isHostname(host string) bool {
+	host = strings.TrimSuffix(host, ".")
+	if host == "" || len(host) > maxDomainLen {
+		return false
+	}
+
+	for _, label := range strings.Split(host, ".") {
+		if label == "" || len(label) > maxLabelLen {
+			return false
+		}
+
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+
+		for i := 0; i < len(label); i++ {
+			c := label[i]
+			isAlnum := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') ||
+				(c >= '0' && c <= '9')
+			if !isAlnum && c != '-' {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func normalizeDomains(domains []string) []string {
+	var normalized []string
+	for _, d := range domains {
+		d = strings.TrimPrefix(strings.TrimSpace(d), "@")
+		if d == "" {
+			continue
+		}
+
+		ascii, err := toASCII(d)
+		if err != nil {
+			ascii = strings.ToLower(d)
+		}
+		normalized = append(normalized, ascii)
+	}
+
+	return normalized
+}
+
+func matchesDomain(domain string, list []string) bool {
+	domain = strings.ToLower(domain)
+	for _, d := range list {
+		if domain == d || strings.HasSuffix(domain, "."+d) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,77 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsEmail(t *testing.T) {
+	tests := []struct {
+		val  interface{}
+		code string
+	}{
+		{nil, ""},
+		{"john@example.com", ""},
+		{"  john@example.com ", ""},
+		{"john.doe+promo@mail.example.co.uk", ""},
+		{"john@bücher.de", ""},
+		{"john", "email"},
+		{"john@", "email"},
+		{"@example.com", "email"},
+		{"john@@example.com", "email"},
+		{"john@localhost", "email.domain"},
+		{"john@-example.com", "email.domain"},
+		{"john@exa_mple.com", "email.domain"},
+		{"John Doe <john@example.com>", "email.display_name"},
+		{"<john@example.com>", "email.display_name"},
+		{strings.Repeat("a", 65) + "@example.com", "email.local_length"},
+		{strings.Repeat("a", 64) + "@example.com", ""},
+		{42, "str.type"},
+	}
+
+	for _, tt := range tests {
+		expectCode(t, "IsEmail", IsEmail(tt.val), tt.code)
+	}
+}
+
+func TestEmailWith(t *testing.T) {
+	tests := []struct {
+		name string
+		opts EmailOptions
+		val  string
+		code string
+	}{
+		{"display name allowed", EmailOptions{AllowDisplayName: true},
+			"John Doe <john@example.com>", ""},
+		{"display name checks address", EmailOptions{AllowDisplayName: true},
+			"John Doe <john@localhost>", "email.domain"},
+		{"allowed domain", EmailOptions{AllowedDomains: []string{"example.com"}},
+			"john@example.com", ""},
+		{"allowed subdomain", EmailOptions{AllowedDomains: []string{"@Example.com"}},
+			"john@mail.example.com", ""},
+		{"not allowed domain", EmailOptions{AllowedDomains: []string{"example.com"}},
+			"john@example.org", "email.domain_denied"},
+		{"suffix is not a subdomain", EmailOptions{AllowedDomains: []string{"example.com"}},
+			"john@badexample.com", "email.domain_denied"},
+		{"allowed idn domain", EmailOptions{AllowedDomains: []string{"bücher.de"}},
+			"john@xn--bcher-kva.de", ""},
+		{"denied domain", EmailOptions{DeniedDomains: []string{"mailinator.com"}},
+			"john@MAILINATOR.com", "email.domain_denied"},
+		{"denied subdomain", EmailOptions{DeniedDomains: []string{"mailinator.com"}},
+			"john@eu.mailinator.com", "email.domain_denied"},
+		{"denied wins over allowed", EmailOptions{AllowedDomains: []string{"example.com"},
+			DeniedDomains: []string{"spam.example.com"}}, "john@spam.example.com",
+			"email.domain_denied"},
+		{"plus allowed", EmailOptions{}, "john+promo@example.com", ""},
+		{"plus disallowed", EmailOptions{DisallowPlus: true},
+			"john+promo@example.com", "email.plus"},
+		{"idn allowed", EmailOptions{}, "john@例え.テスト", ""},
+		{"idn disallowed", EmailOptions{DisallowIDN: true}, "john@bücher.de", "email.idn"},
+		{"punycode is not idn", EmailOptions{DisallowIDN: true},
+			"john@xn--bcher-kva.de", ""},
+	}
+
+	for _, tt := range tests {
+		expectCode(t, tt.name, EmailWith(tt.opts)(tt.val), tt.code)
+	}
+}
@@ -0,0 +1,458 @@
+package checker
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rubikorg/blocks/ds"
+	r "github.com/rubikorg/rubik"
+)
+
+// EntityRule is an assertion over the whole entity, it is used when a
+// rule depends on more than one field of the request. Return a
+// ValidationError with its Field set to report the error against a
+// specific field
+type EntityRule func(entity interface{}) error
+
+// Schema groups the per-field assertions of an entity with the entity
+// rules that need to look at more than one field and the transformers
+// which clean up values before they are checked. Field names can be
+// dot-notation paths like `address.zip` to reach nested objects. Async
+// holds the assertions of a field which need I/O, see ValidateContext.
+// The assertions describe the fields for documentation and SDK
+// generators, Docs adds rules which they cannot tell, i.e: for the
+// context assertions of a field
+type Schema struct {
+	Fields     map[string][]r.Assertion
+	Rules      []EntityRule
+	Transforms map[string][]Transformer
+	Async      map[string][]ContextAssertion
+	Docs       FieldRules
+}
+
+// Field adds assertions to the field of the schema and returns it so that
+// a schema can be written as a chain, i.e:
+//
+//	checker.Schema{}.
+//		Field("name", checker.Required, checker.StrMin(3)).
+//		Field("role", checker.StrIsOneOf("admin", "user"))
+func (s Schema) Field(name string, assertions ...r.Assertion) Schema {
+	if s.Fields == nil {
+		s.Fields = make(map[string][]r.Assertion)
+	}
+
+	s.Fields[name] = append(s.Fields[name], assertions...)
+	return s
+}
+
+// Validate runs the transformers of the schema, then the per-field
+// assertions and then the entity rules. The entity can be a struct, a
+// pointer to a struct or a map with string keys, pass a pointer or a map
+// if the normalized values need to be kept. A nil error is returned if
+// everything passes, otherwise the error is of type ValidationErrors.
+// Context assertions run without a deadline, use ValidateContext to
+// bound them
+func (s Schema) Validate(entity interface{}) error {
+	return s.ValidateContext(context.Background(), entity)
+}
+
+func (s Schema) validate(ctx context.Context, entity interface{}) error {
+	normalized, errs := s.normalize(entity)
+	failed := make(map[string]bool, len(errs))
+	for _, ve := range errs {
+		failed[ve.Field] = true
+	}
+
+	fields := make([]string, 0, len(s.Fields)+len(s.Async))
+	for field := range s.Fields {
+		fields = append(fields, field)
+	}
+	for field := range s.Async {
+		if _, ok := s.Fields[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	var pending []asyncField
+
+	var flat *ds.NotationMap
+	for _, field := range fields {
+		if failed[field] {
+			continue
+		}
+
+		val, found := lookupDirect(entity, field)
+		if nv, ok := normalized[field]; ok {
+			val, found = nv, true
+		}
+
+		if !found && strings.Contains(field, ".") {
+			if flat == nil {
+				nm := flattenEntity(entity)
+				flat = &nm
+			}
+
+			// rules of nested fields only apply when their parent
+			// object is present, the parent has its own rules for that
+			if !hasParents(flat.Map(), field) {
+				continue
+			}
+			val = flat.Get(field)
+		}
+
+		passed := true
+		for _, assert := range s.Fields[field] {
+			if err := assert(val); err != nil {
+				errs = append(errs, bindField(field, err)...)
+				passed = false
+				break
+			}
+		}
+
+		if passed && len(s.Async[field]) > 0 {
+			pending = append(pending, asyncField{name: field, val: val})
+		}
+	}
+
+	errs = append(errs, s.runAsync(ctx, pending)...)
+
+	for _, rule := range s.Rules {
+		errs = append(errs, bindField("", rule(entity))...)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
+
+// Nested validates an object field against the given schema, errors are
+// reported against paths like `address.city`. Use it with Each to
+// validate lists of objects
+func Nested(schema Schema) r.Assertion {
+	return func(val interface{}) error {
+		if val == nil || describes(val) {
+			return nil
+		}
+
+		err := schema.Validate(val)
+		errs, ok := err.(ValidationErrors)
+		if !ok {
+			return err
+		}
+
+		nested := make(ValidationErrors, len(errs))
+		for i, ve := range errs {
+			ve.Field = "$." + ve.Field
+			nested[i] = ve
+		}
+
+		return nested
+	}
+}
+
+// Middleware validates the entity of the request against the schema and
+// throws a 400 with the errors if it fails. Context assertions are bound
+// to the context of the request and messages are localized with the
+// locale of the request
+func (s Schema) Middleware(req *r.Request) {
+	err := s.ValidateContext(req.Raw.Context(), req.Entity)
+	if err != nil {
+		err = Localize(err, LocaleFromRequest(req.Raw))
+		req.Throw(http.StatusBadRequest, err, r.Type.JSON)
+	}
+}
+
+// FieldsEqual checks if the value of field is equal to the value of
+// other, i.e: FieldsEqual("confirm_password", "password")
+func FieldsEqual(field, other string) EntityRule {
+	return func(entity interface{}) error {
+		a, _ := lookupField(entity, field)
+		b, _ := lookupField(entity, other)
+		if !reflect.DeepEqual(a, b) {
+			return fieldErrorf(field, "fields.equal", Params{"other": other},
+				"$ must be equal to %s", other)
+		}
+
+		return nil
+	}
+}
+
+// RequiredOneOf checks if at least one of the given fields is present and
+// not empty, i.e: RequiredOneOf("phone", "email")
+func RequiredOneOf(fields ...string) EntityRule {
+	return func(entity interface{}) error {
+		for _, field := range fields {
+			val, _ := lookupField(entity, field)
+			if !isEmpty(val) {
+				return nil
+			}
+		}
+
+		var errs ValidationErrors
+		for _, field := range fields {
+			errs = append(errs, fieldErrorf(field, "fields.required_one_of",
+				Params{"fields": fields}, "one of %s is required", strings.Join(fields, ", ")))
+		}
+
+		return errs
+	}
+}
+
+// FieldAfter checks if the time value of field is after the time value of
+// other. Both fields can be time.Time values or strings in one of
+// TimeLayouts, the rule is skipped if any of them is missing
+func FieldAfter(field, other string) EntityRule {
+	return TimeFormat(nil).FieldAfter(field, other)
+}
+
+// FieldAfter checks if the time value of field is after the time value of
+// other, strings are read with the layouts of the format
+func (tf TimeFormat) FieldAfter(field, other string) EntityRule {
+	return func(entity interface{}) error {
+		a, _ := lookupField(entity, field)
+		b, _ := lookupField(entity, other)
+		if isEmpty(a) || isEmpty(b) {
+			return nil
+		}
+
+		at, err := tf.toTime(a)
+		if err != nil {
+			return bindField(field, err)
+		}
+
+		bt, err := tf.toTime(b)
+		if err != nil {
+			return bindField(other, err)
+		}
+
+		if !at.After(bt) {
+			return fieldErrorf(field, "fields.after", Params{"other": other},
+				"$ must be after %s", other)
+		}
+
+		return nil
+	}
+}
+
+// fieldErrorf creates a ValidationError which is already bound to field
+func fieldErrorf(field, code string, params Params, format string,
+	args ...interface{}) ValidationError {
+	ve := errorf(code, params, format, args...)
+	ve.Field = field
+	return ve
+}
+
+// lookupField finds the value of a field inside a map with string keys
+// or a struct. Struct fields are matched by their json name or by their
+// Go name ignoring case, fields of embedded structs are searched as
+// well. Dot-notation paths reach into nested objects. The second return
+// value reports if the field exists at all
+func lookupField(entity interface{}, name string) (interface{}, bool) {
+	val, found := lookupDirect(entity, name)
+	if found || !strings.Contains(name, ".") {
+		return val, found
+	}
+
+	val, found = flattenEntity(entity).Map()[name]
+	return val, found
+}
+
+func lookupDirect(entity interface{}, name string) (interface{}, bool) {
+	rv := reflect.ValueOf(entity)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, false
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+
+		v := rv.MapIndex(reflect.ValueOf(name).Convert(rv.Type().Key()))
+		if !v.IsValid() {
+			return nil, false
+		}
+		return indirect(v), true
+	case reflect.Struct:
+		v, ok := structField(rv, name)
+		if !ok {
+			return nil, false
+		}
+		return indirect(v), true
+	}
+
+	return nil, false
+}
+
+func structField(rv reflect.Value, name string) (reflect.Value, bool) {
+	rt := rv.Type()
+	var embedded []reflect.Value
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if sf.Anonymous {
+			fv := rv.Field(i)
+			if fv.Kind() == reflect.Ptr && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				embedded = append(embedded, fv)
+			}
+			continue
+		}
+
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		if fieldName(sf) == name || strings.EqualFold(sf.Name, name) {
+			return rv.Field(i), true
+		}
+	}
+
+	for _, ev := range embedded {
+		if v, ok := structField(ev, name); ok {
+			return v, true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// hasParents reports if every parent object of a dot-notation path is
+// present inside the flattened map
+func hasParents(flat map[string]interface{}, path string) bool {
+	for i := 0; i < len(path); i++ {
+		if path[i] != '.' {
+			continue
+		}
+
+		if v, ok := flat[path[:i]]; !ok || v == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// flattenEntity converts the entity into a flat NotationMap so that
+// nested fields can be read with their dot-notation path
+func flattenEntity(entity interface{}) ds.NotationMap {
+	nm := ds.NewNotationMap()
+	if m, ok := toMap(reflect.ValueOf(entity)); ok {
+		nm.Assign(m)
+		nm.Flatten()
+	}
+
+	return nm
+}
+
+// toMap converts structs and maps with string keys into the
+// map[string]interface{} shape of decoded JSON which NotationMap
+// understands, other values are kept as they are
+func toMap(rv reflect.Value) (map[string]interface{}, bool) {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, false
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+
+		m := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			m[iter.Key().String()] = nestedValue(iter.Value())
+		}
+		return m, true
+	case reflect.Struct:
+		if isLeafStruct(rv.Type()) {
+			return nil, false
+		}
+
+		m := make(map[string]interface{})
+		collectStruct(rv, m)
+		return m, true
+	}
+
+	return nil, false
+}
+
+func collectStruct(rv reflect.Value, m map[string]interface{}) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if sf.Anonymous {
+			fv := rv.Field(i)
+			if fv.Kind() == reflect.Ptr && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				collectStruct(fv, m)
+			}
+			continue
+		}
+
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		m[fieldName(sf)] = nestedValue(rv.Field(i))
+	}
+}
+
+func nestedValue(v reflect.Value) interface{} {
+	if m, ok := toMap(v); ok {
+		return m
+	}
+
+	return indirect(v)
+}
+
+// isLeafStruct reports structs like time.Time which are values on their
+// own and must not be flattened
+func isLeafStruct(rt reflect.Type) bool {
+	return rt == reflect.TypeOf(time.Time{})
+}
+
+// fieldName is the name of a struct field as seen in the request body
+func fieldName(sf reflect.StructField) string {
+	tag := strings.Split(sf.Tag.Get("json"), ",")[0]
+	if tag != "" && tag != "-" {
+		return tag
+	}
+
+	return sf.Name
+}
+
+// indirect returns the value held by v, nil pointers and interfaces are
+// returned as nil
+func indirect(v reflect.Value) interface{} {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if !v.CanInterface() {
+		return nil
+	}
+
+	return v.Interface()
+}
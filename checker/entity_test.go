@@ -0,0 +1,70 @@
+package checker
+
+import (
+	"testing"
+	"time"
+)
+
+type signup struct {
+	Email    string    `json:"email"`
+	Phone    *string   `json:"phone"`
+	Password string    `json:"password"`
+	Confirm  string    `json:"confirm_password"`
+	Start    time.Time `json:"start"`
+	End      string    `json:"end"`
+}
+
+func TestEntityRules(t *testing.T) {
+	phone := "+14155552671"
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		rule   EntityRule
+		entity interface{}
+		field  string
+		code   string
+	}{
+		{"equal struct", FieldsEqual("confirm_password", "password"),
+			signup{Password: "s3cret", Confirm: "s3cret"}, "", ""},
+		{"equal struct mismatch", FieldsEqual("confirm_password", "password"),
+			&signup{Password: "s3cret", Confirm: "secret"}, "confirm_password", "fields.equal"},
+		{"equal map", FieldsEqual("confirm_password", "password"),
+			map[string]interface{}{"password": "a", "confirm_password": "a"}, "", ""},
+		{"equal map missing", FieldsEqual("confirm_password", "password"),
+			map[string]interface{}{"password": "a"}, "confirm_password", "fields.equal"},
+		{"one of struct", RequiredOneOf("phone", "email"), signup{Phone: &phone}, "", ""},
+		{"one of struct empty", RequiredOneOf("phone", "email"), signup{}, "phone",
+			"fields.required_one_of"},
+		{"one of map", RequiredOneOf("phone", "email"),
+			map[string]interface{}{"email": "john@example.com"}, "", ""},
+		{"one of map empty string", RequiredOneOf("phone", "email"),
+			map[string]interface{}{"email": ""}, "phone", "fields.required_one_of"},
+		{"after struct", FieldAfter("end", "start"),
+			signup{Start: start, End: "2024-06-02T00:00:00Z"}, "", ""},
+		{"after struct before", FieldAfter("end", "start"),
+			signup{Start: start, End: "2024-05-31"}, "end", "fields.after"},
+		{"after struct missing", FieldAfter("end", "start"), signup{Start: start}, "", ""},
+		{"after struct invalid", FieldAfter("end", "start"),
+			signup{Start: start, End: "tomorrow"}, "end", "time.type"},
+		{"after map", FieldAfter("end", "start"),
+			map[string]interface{}{"start": "2024-06-01", "end": "2024-06-01T10:00:00Z"}, "", ""},
+		{"after map equal", FieldAfter("end", "start"),
+			map[string]interface{}{"start": "2024-06-01", "end": "2024-06-01"}, "end", "fields.after"},
+	}
+
+	for _, tt := range tests {
+		err := tt.rule(tt.entity)
+		expectCode(t, tt.name, err, tt.code)
+
+		errs := bindField("", err)
+		if len(errs) > 0 && errs[0].Field != tt.field {
+			t.Errorf("%s: got field %q, want %q", tt.name, errs[0].Field, tt.field)
+		}
+	}
+
+	errs := RequiredOneOf("phone", "email")(signup{}).(ValidationErrors)
+	if len(errs) != 2 || errs[1].Field != "email" {
+		t.Errorf("one of: got %v, want an error for both fields", errs)
+	}
+}
@@ -0,0 +1,122 @@
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Params are the parameters of a rule which failed, i.e: {"min": 3} for
+// StrMin(3). They let front-ends build their own messages
+type Params map[string]interface{}
+
+// ValidationError is the error returned by the assertions of checker. It
+// carries a stable rule code like `str.min` and its parameters next to a
+// default english message. The message uses the $ placeholder for the
+// name of the field until the error is bound to a field
+type ValidationError struct {
+	Field   string
+	Code    string
+	Params  Params
+	Message string
+}
+
+// Error returns the default message, the $ placeholder is replaced with
+// the field name if the error is bound to a field
+func (ve ValidationError) Error() string {
+	if ve.Field == "" {
+		return ve.Message
+	}
+
+	return strings.Replace(ve.Message, "$", ve.Field, -1)
+}
+
+// MarshalJSON renders the error with its message resolved against the
+// field name
+func (ve ValidationError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(validationErrorJSON{
+		Field:   ve.Field,
+		Code:    ve.Code,
+		Params:  ve.Params,
+		Message: ve.Error(),
+	})
+}
+
+type validationErrorJSON struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Params  Params `json:"params,omitempty"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors is the list of every error found while validating an
+// entity
+type ValidationErrors []ValidationError
+
+func (ve ValidationErrors) Error() string {
+	msgs := make([]string, len(ve))
+	for i, e := range ve {
+		msgs[i] = e.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// MarshalJSON renders the errors as `{"errors": [...]}` which is the
+// shape sent back in API responses
+func (ve ValidationErrors) MarshalJSON() ([]byte, error) {
+	list := []ValidationError(ve)
+	if list == nil {
+		list = []ValidationError{}
+	}
+
+	return json.Marshal(struct {
+		Errors []ValidationError `json:"errors"`
+	}{list})
+}
+
+// Fields groups the messages of the errors by the name of their field,
+// useful for mapping errors onto form inputs
+func (ve ValidationErrors) Fields() map[string][]string {
+	fields := make(map[string][]string)
+	for _, e := range ve {
+		fields[e.Field] = append(fields[e.Field], e.Error())
+	}
+
+	return fields
+}
+
+// errorf creates a ValidationError which is not yet bound to a field
+func errorf(code string, params Params, format string, args ...interface{}) ValidationError {
+	return ValidationError{
+		Code:    code,
+		Params:  params,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// bindField binds the error of an assertion to the given field. Plain
+// errors of custom assertions get the `invalid` code. A $ inside the
+// field name of an already bound error is replaced with field, so that
+// assertions over elements can report paths like `tags[2]`
+func bindField(field string, err error) ValidationErrors {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case ValidationErrors:
+		var errs ValidationErrors
+		for _, ve := range e {
+			errs = append(errs, bindField(field, ve)...)
+		}
+		return errs
+	case ValidationError:
+		if e.Field == "" {
+			e.Field = field
+		} else if field != "" {
+			e.Field = strings.Replace(e.Field, "$", field, 1)
+		}
+		return ValidationErrors{e}
+	}
+
+	return ValidationErrors{{Field: field, Code: "invalid", Message: err.Error()}}
+}
@@ -0,0 +1,202 @@
+package checker
+
+import (
+	"image"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	// decoders used by ImageDimensions
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	r "github.com/rubikorg/rubik"
+)
+
+// sniffLen is the amount of bytes http.DetectContentType looks at
+const sniffLen = 512
+
+// ImageSize is a bound of ImageDimensions, a zero width or height does
+// not bound that side
+type ImageSize struct {
+	Width  int
+	Height int
+}
+
+// FileMaxSize checks if the uploaded file is not larger than the given
+// amount of bytes. The file assertions check a *multipart.FileHeader
+// which only exists once the form was parsed, by then parts larger than
+// the memory limit of ParseMultipartForm are already written to
+// temporary files. Use Uploads to reject files before they reach the
+// disk
+func FileMaxSize(bytes int64) r.Assertion {
+	return func(val interface{}) error {
+		return checkFile(val, func(fh *multipart.FileHeader) error {
+			if fh.Size > bytes {
+				return maxSizeError(bytes, fh.Size)
+			}
+			return nil
+		})
+	}
+}
+
+// FileMIME checks the type of the uploaded file against the allowed
+// media types, i.e: FileMIME("image/png", "application/pdf"). The type is
+// sniffed from the content of the file, the type sent by the client is
+// not trusted. A type like `image/*` allows every subtype. Only the
+// first 512 bytes are read, from memory or from the temporary file the
+// form parser stored the part in
+func FileMIME(allowed ...string) r.Assertion {
+	return func(val interface{}) error {
+		return checkFile(val, func(fh *multipart.FileHeader) error {
+			detected, err := sniffType(fh)
+			if err != nil {
+				return err
+			}
+
+			return checkMIME(detected, allowed)
+		})
+	}
+}
+
+// FileExt checks the extension of the name of the uploaded file, the
+// extensions can be given with or without the leading dot and are
+// compared ignoring case, i.e: FileExt("jpg", "jpeg")
+func FileExt(exts ...string) r.Assertion {
+	return func(val interface{}) error {
+		return checkFile(val, func(fh *multipart.FileHeader) error {
+			ext := strings.TrimPrefix(filepath.Ext(fh.Filename), ".")
+			for _, e := range exts {
+				if ext != "" && strings.EqualFold(ext, strings.TrimPrefix(e, ".")) {
+					return nil
+				}
+			}
+
+			return errorf("file.ext", Params{"allowed": exts},
+				"$ must have one of the extensions %s", strings.Join(exts, ", "))
+		})
+	}
+}
+
+// ImageDimensions checks if the uploaded file is a PNG, JPEG or GIF image
+// whose size lies between min and max. Only the header of the image is
+// decoded so that large images are not loaded into memory, i.e: ImageDimensions(ImageSize{100, 100}, ImageSize{4096, 4096})
+func ImageDimensions(min, max ImageSize) r.Assertion {
+	return func(val interface{}) error {
+		return checkFile(val, func(fh *multipart.FileHeader) error {
+			f, err := fh.Open()
+			if err != nil {
+				return errorf("file.read", nil, "$ could not be read")
+			}
+			defer f.Close()
+
+			cfg, _, err := image.DecodeConfig(f)
+			if err != nil {
+				return errorf("file.image", nil, "$ must be a PNG, JPEG or GIF image")
+			}
+
+			return checkDimensions(cfg.Width, cfg.Height, min, max)
+		})
+	}
+}
+
+// String formats the size like 640x480, unbounded sides are shown as *
+func (s ImageSize) String() string {
+	side := func(n int) string {
+		if n <= 0 {
+			return "*"
+		}
+		return strconv.Itoa(n)
+	}
+
+	return side(s.Width) + "x" + side(s.Height)
+}
+
+func maxSizeError(max, size int64) error {
+	return errorf("file.max_size", Params{"max": max, "size": size},
+		"$ must not be larger than %d bytes", max)
+}
+
+// checkMIME matches the sniffed type of a file against the allowed types
+func checkMIME(detected string, allowed []string) error {
+	if i := strings.Index(detected, ";"); i >= 0 {
+		detected = detected[:i]
+	}
+	detected = strings.TrimSpace(detected)
+
+	for _, t := range allowed {
+		if matchesMIME(detected, t) {
+			return nil
+		}
+	}
+
+	return errorf("file.mime", Params{"allowed": allowed, "detected": detected},
+		"$ must be of type %s", strings.Join(allowed, " or "))
+}
+
+func checkDimensions(width, height int, min, max ImageSize) error {
+	if outside(width, min.Width, max.Width) || outside(height, min.Height, max.Height) {
+		return errorf("file.dimensions", Params{
+			"min_width": min.Width, "min_height": min.Height,
+			"max_width": max.Width, "max_height": max.Height,
+			"width": width, "height": height,
+		}, "$ must be between %s and %s pixels", min, max)
+	}
+
+	return nil
+}
+
+func outside(n, min, max int) bool {
+	return (min > 0 && n < min) || (max > 0 && n > max)
+}
+
+// checkFile passes nil values and reports values which are not uploaded
+// files
+func checkFile(val interface{}, check func(*multipart.FileHeader) error) error {
+	var fh *multipart.FileHeader
+	switch v := val.(type) {
+	case nil:
+		return nil
+	case *multipart.FileHeader:
+		if v == nil {
+			return nil
+		}
+		fh = v
+	case multipart.FileHeader:
+		fh = &v
+	default:
+		return errorf("file.type", nil, "$ must be an uploaded file")
+	}
+
+	return check(fh)
+}
+
+// sniffType reads the start of the file and returns its media type
+func sniffType(fh *multipart.FileHeader) (string, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return "", errorf("file.read", nil, "$ could not be read")
+	}
+	defer f.Close()
+
+	buf := make([]byte, sniffLen)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", errorf("file.read", nil, "$ could not be read")
+	}
+
+	return http.DetectContentType(buf[:n]), nil
+}
+
+func matchesMIME(detected, allowed string) bool {
+	allowed = strings.ToLower(strings.TrimSpace(allowed))
+	if strings.HasSuffix(allowed, "/*") {
+		return strings.HasPrefix(detected, strings.TrimSuffix(allowed, "*"))
+	}
+
+	return detected == allowed
+}
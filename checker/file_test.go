@@ -0,0 +1,73 @@
+package checker
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"testing"
+)
+
+// uploadFile builds a multipart form holding content and returns the
+// header of its file part like the form parser does
+func uploadFile(t *testing.T, name string, content []byte) *multipart.FileHeader {
+	t.Helper()
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("file", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(content)
+	w.Close()
+
+	form, err := multipart.NewReader(&body, w.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return form.File["file"][0]
+}
+
+func pngImage(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestFileAssertions(t *testing.T) {
+	photo := uploadFile(t, "photo.PNG", pngImage(t, 200, 100))
+	text := uploadFile(t, "photo.png", []byte("just some text"))
+	bounds := ImageDimensions(ImageSize{100, 100}, ImageSize{400, 0})
+
+	tests := []struct {
+		name string
+		err  error
+		code string
+	}{
+		{"nil", FileMaxSize(1)(nil), ""},
+		{"not a file", FileMaxSize(1)("photo.png"), "file.type"},
+		{"size", FileMaxSize(photo.Size)(photo), ""},
+		{"too large", FileMaxSize(photo.Size - 1)(photo), "file.max_size"},
+		{"mime", FileMIME("image/png")(photo), ""},
+		{"mime wildcard", FileMIME("application/pdf", "image/*")(photo), ""},
+		{"mime is sniffed", FileMIME("image/png")(text), "file.mime"},
+		{"ext ignores case", FileExt(".png")(photo), ""},
+		{"ext without dot", FileExt("jpg", "png")(text), ""},
+		{"ext", FileExt("jpg")(photo), "file.ext"},
+		{"dimensions", bounds(photo), ""},
+		{"dimensions too small", ImageDimensions(ImageSize{Height: 200}, ImageSize{})(photo),
+			"file.dimensions"},
+		{"dimensions too large", ImageDimensions(ImageSize{}, ImageSize{Width: 199})(photo),
+			"file.dimensions"},
+		{"not an image", bounds(text), "file.image"},
+	}
+
+	for _, tt := range tests {
+		expectCode(t, tt.name, tt.err, tt.code)
+	}
+}
@@ -0,0 +1,325 @@
+package checker
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	r "github.com/rubikorg/rubik"
+)
+
+var (
+	uuidRegex     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hexColorRegex = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{4}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+	e164Regex     = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+	ibanRegex     = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]{11,30}$`)
+	// semverRegex is the regular expression suggested by semver.org
+	semverRegex = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+		`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+		`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+)
+
+// IsUUID checks if the value is a UUID in its canonical 8-4-4-4-12 form
+func IsUUID(val interface{}) error {
+	return checkFormat(val, "format.uuid", "$ must be a valid UUID", uuidRegex.MatchString)
+}
+
+// UUIDWith checks if the value is a RFC 4122 UUID of the given version
+func UUIDWith(version int) r.Assertion {
+	return func(val interface{}) error {
+		return checkFormat(val, "format.uuid", "$ must be a valid UUID", func(s string) bool {
+			if !uuidRegex.MatchString(s) {
+				return false
+			}
+
+			variant := strings.ToLower(s[19:20])
+			return int(s[14]-'0') == version && strings.Contains("89ab", variant)
+		})
+	}
+}
+
+// IsURL checks if the value is an absolute URL with a scheme and a host
+func IsURL(val interface{}) error {
+	return checkFormat(val, "format.url", "$ must be a valid URL", func(s string) bool {
+		return isURL(s, nil)
+	})
+}
+
+// URLWith checks if the value is an absolute URL which uses one of the
+// given schemes, i.e: URLWith("https")
+func URLWith(schemes ...string) r.Assertion {
+	return func(val interface{}) error {
+		return checkFormat(val, "format.url", "$ must be a valid URL", func(s string) bool {
+			return isURL(s, schemes)
+		})
+	}
+}
+
+// IsIP checks if the value is an IPv4 or IPv6 address
+func IsIP(val interface{}) error {
+	return checkFormat(val, "format.ip", "$ must be a valid IP address", func(s string) bool {
+		return net.ParseIP(s) != nil
+	})
+}
+
+// IsIPv4 checks if the value is an IPv4 address in dotted form like
+// `10.0.0.1`, IPv4-mapped IPv6 addresses are rejected
+func IsIPv4(val interface{}) error {
+	return checkFormat(val, "format.ipv4", "$ must be a valid IPv4 address", func(s string) bool {
+		return !strings.Contains(s, ":") && net.ParseIP(s).To4() != nil
+	})
+}
+
+// IsIPv6 checks if the value is an IPv6 address, IPv4 addresses in dotted
+// form are rejected while IPv4-mapped ones like `::ffff:10.0.0.1` pass
+func IsIPv6(val interface{}) error {
+	return checkFormat(val, "format.ipv6", "$ must be a valid IPv6 address", func(s string) bool {
+		return strings.Contains(s, ":") && net.ParseIP(s) != nil
+	})
+}
+
+// IsCIDR checks if the value is an IP network in CIDR notation like
+// `10.0.0.0/8`
+func IsCIDR(val interface{}) error {
+	return checkFormat(val, "format.cidr", "$ must be a valid CIDR", func(s string) bool {
+		_, _, err := net.ParseCIDR(s)
+		return err == nil
+	})
+}
+
+// IsHostname checks if the value is a valid host name, internationalized
+// names are checked in their punycode form
+func IsHostname(val interface{}) error {
+	return checkFormat(val, "format.hostname", "$ must be a valid hostname", func(s string) bool {
+		ascii, err := toASCII(s)
+		return err == nil && isHostname(ascii)
+	})
+}
+
+// IsHexColor checks if the value is a CSS hex color like #fff or #1a2b3c
+func IsHexColor(val interface{}) error {
+	return checkFormat(val, "format.hex_color", "$ must be a valid hex color",
+		hexColorRegex.MatchString)
+}
+
+// IsSemver checks if the value is a semantic version like 1.4.0-beta.1
+func IsSemver(val interface{}) error {
+	return checkFormat(val, "format.semver", "$ must be a valid semantic version",
+		semverRegex.MatchString)
+}
+
+// IsE164Phone checks if the value is a phone number in the E.164 format
+// like +14155552671
+func IsE164Phone(val interface{}) error {
+	return checkFormat(val, "format.e164", "$ must be a phone number in E.164 format",
+		e164Regex.MatchString)
+}
+
+// IsLuhn checks if the value passes the Luhn checksum used by credit card
+// numbers, spaces and hyphens between digits are ignored
+func IsLuhn(val interface{}) error {
+	return checkFormat(val, "format.luhn", "$ must be a valid card number", func(s string) bool {
+		return luhn(stripSeparators(s))
+	})
+}
+
+// IsISBN checks if the value is a valid ISBN-10 or ISBN-13, spaces and
+// hyphens are ignored
+func IsISBN(val interface{}) error {
+	return checkFormat(val, "format.isbn", "$ must be a valid ISBN", func(s string) bool {
+		s = stripSeparators(s)
+		switch len(s) {
+		case 10:
+			return isbn10(s)
+		case 13:
+			return isbn13(s)
+		}
+		return false
+	})
+}
+
+// IsIBAN checks if the value is an IBAN with a valid mod 97 checksum,
+// spaces are ignored
+func IsIBAN(val interface{}) error {
+	return checkFormat(val, "format.iban", "$ must be a valid IBAN", func(s string) bool {
+		s = strings.ToUpper(strings.Replace(s, " ", "", -1))
+		return ibanRegex.MatchString(s) && iban(s)
+	})
+}
+
+// IsBase64 checks if the value is padded standard or URL safe base64
+func IsBase64(val interface{}) error {
+	return checkFormat(val, "format.base64", "$ must be base64 encoded", func(s string) bool {
+		if _, err := base64.StdEncoding.DecodeString(s); err == nil {
+			return true
+		}
+		_, err := base64.URLEncoding.DecodeString(s)
+		return err == nil
+	})
+}
+
+// IsJSON checks if the value is a valid JSON document
+func IsJSON(val interface{}) error {
+	return checkFormat(val, "format.json", "$ must be valid JSON", func(s string) bool {
+		return json.Valid([]byte(s))
+	})
+}
+
+// IsISO8601Date checks if the value is a calendar date like 2020-06-01
+func IsISO8601Date(val interface{}) error {
+	return checkFormat(val, "format.date", "$ must be a date like 2006-01-02", func(s string) bool {
+		_, err := time.Parse("2006-01-02", s)
+		return err == nil
+	})
+}
+
+// formatNames are the JSON Schema formats of the format error codes,
+// formats without a name are checked but not described
+var formatNames = map[string]string{
+	"format.uuid":     "uuid",
+	"format.url":      "uri",
+	"format.ip":       "ip",
+	"format.ipv4":     "ipv4",
+	"format.ipv6":     "ipv6",
+	"format.cidr":     "cidr",
+	"format.hostname": "hostname",
+	"format.semver":   "semver",
+	"format.e164":     "e164",
+	"format.base64":   "base64",
+	"format.json":     "json",
+	"format.date":     "date",
+}
+
+// checkFormat follows the nil handling of IsStr and reports the given
+// message when the string value is not valid
+func checkFormat(val interface{}, code, msg string, valid func(string) bool) error {
+	var rules []Rule
+	if format, ok := formatNames[code]; ok {
+		rules = append(rules, Rule{"format", format})
+	}
+	if describes(val, rules...) {
+		return nil
+	}
+
+	err := IsStr(val)
+	if err != nil || val == nil {
+		return err
+	}
+
+	if !valid(strValue(val)) {
+		return errorf(code, nil, msg)
+	}
+
+	return nil
+}
+
+func isURL(s string, schemes []string) bool {
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return false
+	}
+
+	if len(schemes) == 0 {
+		return true
+	}
+
+	for _, scheme := range schemes {
+		if strings.EqualFold(u.Scheme, scheme) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func stripSeparators(s string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(s)
+}
+
+func luhn(s string) bool {
+	if len(s) < 2 {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(s) - 1; i >= 0; i-- {
+		d := int(s[i] - '0')
+		if d < 0 || d > 9 {
+			return false
+		}
+
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+
+	return sum%10 == 0
+}
+
+func isbn10(s string) bool {
+	sum := 0
+	for i := 0; i < 10; i++ {
+		var d int
+		switch {
+		case s[i] >= '0' && s[i] <= '9':
+			d = int(s[i] - '0')
+		case i == 9 && (s[i] == 'X' || s[i] == 'x'):
+			d = 10
+		default:
+			return false
+		}
+		sum += d * (10 - i)
+	}
+
+	return sum%11 == 0
+}
+
+func isbn13(s string) bool {
+	sum := 0
+	for i := 0; i < 13; i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+
+		d := int(s[i] - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+
+	return sum%10 == 0
+}
+
+// iban moves the country code and check digits to the end, turns letters
+// into numbers and checks that the result mod 97 is 1
+func iban(s string) bool {
+	rearranged := s[4:] + s[:4]
+	var digits strings.Builder
+	for _, c := range rearranged {
+		if c >= 'A' && c <= 'Z' {
+			digits.WriteString(strconv.Itoa(int(c-'A') + 10))
+		} else {
+			digits.WriteRune(c)
+		}
+	}
+
+	n, ok := new(big.Int).SetString(digits.String(), 10)
+	if !ok {
+		return false
+	}
+
+	return new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
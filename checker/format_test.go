@@ -0,0 +1,55 @@
+package checker
+
+import "testing"
+
+func TestFormats(t *testing.T) {
+	tests := []struct {
+		name   string
+		assert func(interface{}) error
+		val    string
+		code   string
+	}{
+		{"luhn card", IsLuhn, "4111 1111 1111 1111", ""},
+		{"luhn digits", IsLuhn, "79927398713", ""},
+		{"luhn checksum", IsLuhn, "79927398710", "format.luhn"},
+		{"luhn letters", IsLuhn, "7992739871a", "format.luhn"},
+		{"isbn10", IsISBN, "0-306-40615-2", ""},
+		{"isbn10 x check digit", IsISBN, "080442957X", ""},
+		{"isbn10 checksum", IsISBN, "0-306-40615-3", "format.isbn"},
+		{"isbn13", IsISBN, "978-0-306-40615-7", ""},
+		{"isbn13 checksum", IsISBN, "978-0-306-40615-6", "format.isbn"},
+		{"isbn length", IsISBN, "978030640615", "format.isbn"},
+		{"iban gb", IsIBAN, "GB82 WEST 1234 5698 7654 32", ""},
+		{"iban de lower case", IsIBAN, "de89370400440532013000", ""},
+		{"iban checksum", IsIBAN, "GB82 WEST 1234 5698 7654 33", "format.iban"},
+		{"iban shape", IsIBAN, "82GB WEST 1234 5698 7654 32", "format.iban"},
+		{"uuid", IsUUID, "123e4567-e89b-12d3-a456-426614174000", ""},
+		{"uuid shape", IsUUID, "123e4567e89b12d3a456426614174000", "format.uuid"},
+		{"url", IsURL, "https://example.com/a?b=c", ""},
+		{"url without host", IsURL, "mailto:john@example.com", "format.url"},
+		{"hostname idn", IsHostname, "bücher.de", ""},
+		{"hostname underscore", IsHostname, "my_host.com", "format.hostname"},
+		{"hex color", IsHexColor, "#1a2B3c", ""},
+		{"hex color length", IsHexColor, "#1a2b3", "format.hex_color"},
+		{"semver", IsSemver, "1.4.0-beta.1+build.5", ""},
+		{"semver leading zero", IsSemver, "01.4.0", "format.semver"},
+		{"e164", IsE164Phone, "+14155552671", ""},
+		{"e164 without plus", IsE164Phone, "14155552671", "format.e164"},
+		{"cidr", IsCIDR, "10.0.0.0/8", ""},
+		{"cidr without mask", IsCIDR, "10.0.0.0", "format.cidr"},
+		{"base64", IsBase64, "aGVsbG8=", ""},
+		{"base64 url", IsBase64, "-_-_", ""},
+		{"base64 padding", IsBase64, "aGVsbG8", "format.base64"},
+		{"json", IsJSON, `{"a": [1, 2]}`, ""},
+		{"json broken", IsJSON, `{"a": }`, "format.json"},
+		{"date", IsISO8601Date, "2024-02-29", ""},
+		{"date not in calendar", IsISO8601Date, "2023-02-29", "format.date"},
+	}
+
+	for _, tt := range tests {
+		expectCode(t, tt.name, tt.assert(tt.val), tt.code)
+	}
+
+	expectCode(t, "uuid version", UUIDWith(4)("123e4567-e89b-12d3-a456-426614174000"), "format.uuid")
+	expectCode(t, "url scheme", URLWith("https")("http://example.com"), "format.url")
+}
@@ -0,0 +1,197 @@
+package checker
+
+import "unicode"
+
+const (
+	zwj  = '\u200d'
+	zwnj = '\u200c'
+)
+
+// graphemeState carries what the segmentation needs to remember about
+// the cluster which is being read
+type graphemeState struct {
+	// regional indicators at the end of the cluster, flags are pairs
+	riCount int
+	// the cluster is an emoji followed only by extenders
+	inPict bool
+	// the previous rune is a zero width joiner which follows an emoji
+	zwjAfterPict bool
+	// 1 after an indic consonant, 2 after consonant and virama
+	incb int
+}
+
+// graphemeCount counts the user-perceived characters of s. It follows
+// the extended grapheme cluster rules of UAX #29 closely enough for
+// combining marks, Hangul, emoji sequences, flags and the conjuncts of
+// the common Indic scripts without carrying the full unicode tables
+func graphemeCount(s string) int {
+	count := 0
+	var prev rune
+	var st graphemeState
+	for i, c := range s {
+		if i == 0 || graphemeBreak(prev, c, st) {
+			count++
+			st = graphemeState{}
+		}
+
+		st.update(c)
+		prev = c
+	}
+
+	return count
+}
+
+func graphemeBreak(p, c rune, st graphemeState) bool {
+	switch {
+	case p == '\r' && c == '\n':
+		return false
+	case isGraphemeControl(p) || isGraphemeControl(c):
+		return true
+	case hangulJoins(p, c):
+		return false
+	case isGraphemeExtend(c) || c == zwj || unicode.Is(unicode.Mc, c):
+		return false
+	case st.incb == 2 && isIndicConsonant(c):
+		return false
+	case p == zwj && st.zwjAfterPict && isPictographic(c):
+		return false
+	case isRegionalIndicator(p) && isRegionalIndicator(c) && st.riCount%2 == 1:
+		return false
+	}
+
+	return true
+}
+
+func (st *graphemeState) update(c rune) {
+	if isRegionalIndicator(c) {
+		st.riCount++
+	} else {
+		st.riCount = 0
+	}
+
+	switch {
+	case isPictographic(c):
+		st.inPict, st.zwjAfterPict = true, false
+	case c == zwj:
+		st.zwjAfterPict, st.inPict = st.inPict, false
+	case isGraphemeExtend(c):
+		st.zwjAfterPict = false
+	default:
+		st.inPict, st.zwjAfterPict = false, false
+	}
+
+	switch {
+	case isIndicConsonant(c):
+		st.incb = 1
+	case isIndicLinker(c) && st.incb >= 1:
+		st.incb = 2
+	case isGraphemeExtend(c) || c == zwj:
+	default:
+		st.incb = 0
+	}
+}
+
+func isGraphemeControl(c rune) bool {
+	if c == zwj || c == zwnj || (c >= 0xE0020 && c <= 0xE007F) {
+		return false
+	}
+
+	return unicode.IsControl(c) || unicode.In(c, unicode.Zl, unicode.Zp, unicode.Cf)
+}
+
+func isGraphemeExtend(c rune) bool {
+	return unicode.In(c, unicode.Mn, unicode.Me, unicode.Other_Grapheme_Extend) ||
+		c == zwnj ||
+		(c >= 0x1F3FB && c <= 0x1F3FF) || // emoji skin tone modifiers
+		(c >= 0xE0020 && c <= 0xE007F) // emoji tag sequences
+}
+
+func isRegionalIndicator(c rune) bool {
+	return c >= 0x1F1E6 && c <= 0x1F1FF
+}
+
+// isPictographic approximates the Extended_Pictographic property with
+// the blocks which hold emoji
+func isPictographic(c rune) bool {
+	switch {
+	case c == 0x00A9, c == 0x00AE, c == 0x203C, c == 0x2049, c == 0x2122,
+		c == 0x2139, c == 0x3030, c == 0x303D, c == 0x3297, c == 0x3299:
+		return true
+	case c >= 0x2190 && c <= 0x21FF, c >= 0x2300 && c <= 0x23FF,
+		c >= 0x25A0 && c <= 0x27BF, c >= 0x2900 && c <= 0x297F,
+		c >= 0x2B00 && c <= 0x2BFF:
+		return true
+	case c >= 0x1F000 && c <= 0x1FAFF && !isRegionalIndicator(c) &&
+		!(c >= 0x1F3FB && c <= 0x1F3FF):
+		return true
+	}
+
+	return false
+}
+
+// hangulJoins implements the rules which keep the jamo of a Hangul
+// syllable together
+func hangulJoins(p, c rune) bool {
+	pl, pv, pt := isHangulL(p), isHangulV(p), isHangulT(p)
+	plv, plvt := isHangulLV(p), isHangulLVT(p)
+	cl, cv, ct := isHangulL(c), isHangulV(c), isHangulT(c)
+	clv, clvt := isHangulLV(c), isHangulLVT(c)
+
+	switch {
+	case pl && (cl || cv || clv || clvt):
+		return true
+	case (plv || pv) && (cv || ct):
+		return true
+	case (plvt || pt) && ct:
+		return true
+	}
+
+	return false
+}
+
+func isHangulL(c rune) bool {
+	return (c >= 0x1100 && c <= 0x115F) || (c >= 0xA960 && c <= 0xA97C)
+}
+
+func isHangulV(c rune) bool {
+	return (c >= 0x1160 && c <= 0x11A7) || (c >= 0xD7B0 && c <= 0xD7C6)
+}
+
+func isHangulT(c rune) bool {
+	return (c >= 0x11A8 && c <= 0x11FF) || (c >= 0xD7CB && c <= 0xD7FB)
+}
+
+func isHangulLV(c rune) bool {
+	return c >= 0xAC00 && c <= 0xD7A3 && (c-0xAC00)%28 == 0
+}
+
+func isHangulLVT(c rune) bool {
+	return c >= 0xAC00 && c <= 0xD7A3 && (c-0xAC00)%28 != 0
+}
+
+// indicScripts are the scripts whose consonant clusters joined by a
+// virama are shown as a single character
+var indicScripts = []*unicode.RangeTable{
+	unicode.Devanagari, unicode.Bengali, unicode.Gujarati,
+	unicode.Oriya, unicode.Telugu, unicode.Malayalam,
+}
+
+func isIndicConsonant(c rune) bool {
+	if c < 0x0900 || c > 0x0D7F || !unicode.Is(unicode.Lo, c) {
+		return false
+	}
+
+	// independent vowels sit at the start of each block, consonants
+	// follow them at the same offsets in all of these scripts
+	offset := (c - 0x0900) % 0x80
+	return offset >= 0x15 && offset <= 0x39 && unicode.IsOneOf(indicScripts, c)
+}
+
+func isIndicLinker(c rune) bool {
+	switch c {
+	case 0x094D, 0x09CD, 0x0ACD, 0x0B4D, 0x0C4D, 0x0D4D:
+		return true
+	}
+
+	return false
+}
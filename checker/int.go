@@ -2,37 +2,217 @@ package checker
 
 import (
 	"fmt"
-	"strconv"
 
 	r "github.com/rubikorg/rubik"
 )
 
+var (
+	errNotNumber  = errorf("num.type", nil, "$ is not a number")
+	errNotInteger = errorf("num.integer", nil, "$ is not an integer")
+	errOverflow   = errorf("num.overflow", nil, "$ is too large to be an integer")
+)
+
+// IsZero checks if the value of the field is numerically zero. Go
+// numbers, json.Number and numeric strings are accepted
 func IsZero(val interface{}) error {
-	err := IsStr(val)
-	if err != nil {
-		return err
+	if val == nil {
+		return nil
 	}
 
-	tgt, err := strconv.Atoi(val.(string))
+	tgt, err := AsFloat(val)
 	if err != nil {
-		return fmt.Errorf("%v cannot be converted into an integer", val)
+		return err
 	}
 
 	if tgt != 0 {
-		return fmt.Errorf("value %v does not equate to zero", val)
+		return errorf("num.zero", nil, "value %v does not equate to zero", val)
 	}
 
 	return nil
 }
 
+// IntMust only allows the request field to be the given integer. The
+// value may be any integer type, json.Number or an integral string, it
+// panics if it is not an integer which happens when the assertion is
+// created
 func IntMust(value interface{}) r.Assertion {
+	want := mustInt("IntMust", value)
 	return func(val interface{}) error {
+		if val == nil || describes(val, Rule{"enum", []int64{want}}) {
+			return nil
+		}
+
+		i, err := AsInt(val)
+		if err != nil {
+			return err
+		}
+
+		if i != want {
+			return errorf("int.equal", Params{"value": want}, "$ must be equal to %d", want)
+		}
+
 		return nil
 	}
 }
 
+// IntIsOneOf allowes only the integers passed inside this method
+// as a viable value for the request field. Values are coerced like the
+// value of IntMust
 func IntIsOneOf(values ...interface{}) r.Assertion {
+	allowed := make([]int64, len(values))
+	for i, v := range values {
+		allowed[i] = mustInt("IntIsOneOf", v)
+	}
+
+	return func(val interface{}) error {
+		if val == nil || len(allowed) == 0 || describes(val, Rule{"enum", allowed}) {
+			return nil
+		}
+
+		i, err := AsInt(val)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range allowed {
+			if v == i {
+				return nil
+			}
+		}
+
+		return errorf("int.oneof", Params{"values": allowed}, "$ must be one of %v", allowed)
+	}
+}
+
+// IntMin checks if the integer value of the field is at least min
+func IntMin(min int) r.Assertion {
+	return func(val interface{}) error {
+		if val == nil || describes(val, Rule{"minimum", min}) {
+			return nil
+		}
+
+		i, err := AsInt(val)
+		if err != nil {
+			return err
+		}
+
+		if i < int64(min) {
+			return errorf("int.min", Params{"min": min}, "$ must be at least %d", min)
+		}
+
+		return nil
+	}
+}
+
+// IntMax checks if the integer value of the field is at most max
+func IntMax(max int) r.Assertion {
+	return func(val interface{}) error {
+		if val == nil || describes(val, Rule{"maximum", max}) {
+			return nil
+		}
+
+		i, err := AsInt(val)
+		if err != nil {
+			return err
+		}
+
+		if i > int64(max) {
+			return errorf("int.max", Params{"max": max}, "$ must be at most %d", max)
+		}
+
+		return nil
+	}
+}
+
+// IntRange checks if the integer value of the field lies between min
+// and max, both inclusive
+func IntRange(min, max int) r.Assertion {
+	return func(val interface{}) error {
+		if val == nil || describes(val, Rule{"minimum", min}, Rule{"maximum", max}) {
+			return nil
+		}
+
+		i, err := AsInt(val)
+		if err != nil {
+			return err
+		}
+
+		if i < int64(min) || i > int64(max) {
+			return errorf("int.range", Params{"min": min, "max": max},
+				"$ must be between %d and %d", min, max)
+		}
+
+		return nil
+	}
+}
+
+// FloatRange checks if the numeric value of the field lies between min
+// and max, both inclusive. Integers are accepted as well
+func FloatRange(min, max float64) r.Assertion {
 	return func(val interface{}) error {
+		if val == nil || describes(val, Rule{"minimum", min}, Rule{"maximum", max}) {
+			return nil
+		}
+
+		f, err := AsFloat(val)
+		if err != nil {
+			return err
+		}
+
+		if f < min || f > max {
+			return errorf("float.range", Params{"min": min, "max": max},
+				"$ must be between %g and %g", min, max)
+		}
+
+		return nil
+	}
+}
+
+// Positive checks if the numeric value of the field is greater than zero
+func Positive(val interface{}) error {
+	if val == nil || describes(val, Rule{"exclusiveMinimum", 0}) {
 		return nil
 	}
+
+	f, err := AsFloat(val)
+	if err != nil {
+		return err
+	}
+
+	if f <= 0 {
+		return errorf("num.positive", nil, "$ must be a positive number")
+	}
+
+	return nil
+}
+
+// MultipleOf checks if the integer value of the field is a multiple of n
+func MultipleOf(n int) r.Assertion {
+	return func(val interface{}) error {
+		if val == nil || describes(val, Rule{"multipleOf", n}) {
+			return nil
+		}
+
+		i, err := AsInt(val)
+		if err != nil {
+			return err
+		}
+
+		if (n == 0 && i != 0) || (n != 0 && i%int64(n) != 0) {
+			return errorf("int.multiple_of", Params{"n": n}, "$ must be a multiple of %d", n)
+		}
+
+		return nil
+	}
+}
+
+// mustInt coerces an argument of an assertion constructor into an
+// integer and panics if it is not one
+func mustInt(fn string, value interface{}) int64 {
+	i, err := AsInt(value)
+	if err != nil {
+		panic(fmt.Sprintf("checker: %s: %v is not an integer", fn, value))
+	}
+
+	return i
 }
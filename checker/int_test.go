@@ -0,0 +1,34 @@
+package checker
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestIntMust(t *testing.T) {
+	for _, want := range []interface{}{3, int64(3), uint8(3), json.Number("3"), "3"} {
+		check := IntMust(want)
+		expectCode(t, "IntMust match", check(3.0), "")
+		expectCode(t, "IntMust json", check(json.Number("3")), "")
+		expectCode(t, "IntMust other", check(4), "int.equal")
+		expectCode(t, "IntMust nil", check(nil), "")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("IntMust accepted a non integer value")
+		}
+	}()
+	IntMust(1.5)
+}
+
+func TestIntIsOneOf(t *testing.T) {
+	var values []interface{}
+	json.Unmarshal([]byte(`[1, 2, 3]`), &values)
+	check := IntIsOneOf(values...)
+	expectCode(t, "decoded values", check(int64(2)), "")
+	expectCode(t, "string value", check("3"), "")
+	expectCode(t, "missing value", check(4), "int.oneof")
+	expectCode(t, "mixed types", IntIsOneOf(int32(7), json.Number("8"))(8), "")
+	expectCode(t, "no values", IntIsOneOf()(5), "")
+}
@@ -0,0 +1,441 @@
+package checker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	r "github.com/rubikorg/rubik"
+)
+
+// JSONSchema is a compiled JSON Schema document. It supports the subset
+// of draft-07 used to describe request bodies: type, properties,
+// required, additionalProperties, enum, pattern, minLength, maxLength,
+// format, minimum, maximum, exclusiveMinimum, exclusiveMaximum, items,
+// minItems, maxItems and uniqueItems. Other keywords are ignored, $ref
+// is refused. Compile schemas once at startup and reuse them
+type JSONSchema struct {
+	root *jsonNode
+}
+
+// jsonNode is a compiled schema, the checks of a type only run for
+// values of that type like JSON Schema keywords do
+type jsonNode struct {
+	types      []string
+	enum       []interface{}
+	strChecks  []r.Assertion
+	numChecks  []r.Assertion
+	listChecks []r.Assertion
+	properties map[string]*jsonNode
+	required   []string
+	closed     bool
+	items      *jsonNode
+}
+
+// jsonFormats maps the formats of JSON Schema to the assertions of the
+// checker
+var jsonFormats = map[string]r.Assertion{
+	"email":     IsEmail,
+	"uri":       IsURL,
+	"uuid":      IsUUID,
+	"hostname":  IsHostname,
+	"ipv4":      IsIPv4,
+	"ipv6":      IsIPv6,
+	"date":      IsISO8601Date,
+	"date-time": TimeLayout(time.RFC3339Nano),
+}
+
+// CompileJSONSchema reads a JSON Schema document and compiles it
+func CompileJSONSchema(doc []byte) (*JSONSchema, error) {
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+	var raw map[string]interface{}
+	if err := dec.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("checker: invalid JSON schema: %v", err)
+	}
+
+	root, err := compileJSONNode(raw, "#")
+	if err != nil {
+		return nil, fmt.Errorf("checker: %v", err)
+	}
+
+	return &JSONSchema{root: root}, nil
+}
+
+// LoadJSONSchema compiles the JSON Schema stored in the file at path
+func LoadJSONSchema(path string) (*JSONSchema, error) {
+	doc, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return CompileJSONSchema(doc)
+}
+
+// MustCompileJSONSchema is like CompileJSONSchema but panics if the
+// document cannot be compiled
+func MustCompileJSONSchema(doc []byte) *JSONSchema {
+	s, err := CompileJSONSchema(doc)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+// Validate checks a decoded body against the schema. Bodies decoded
+// into other types than maps and slices, like entities, are read through
+// their JSON form. A nil error is returned if the body is valid,
+// otherwise the error is of type ValidationErrors with fields named like
+// `address.city` and `tags[2]`
+func (s *JSONSchema) Validate(body interface{}) error {
+	val, err := toJSONValue(body)
+	if err != nil {
+		return err
+	}
+
+	var errs ValidationErrors
+	s.root.validate(val, "", &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
+
+// Middleware validates the entity of the request against the schema and
+// throws a 400 with the localized errors if it fails
+func (s *JSONSchema) Middleware(req *r.Request) {
+	err := s.Validate(req.Entity)
+	if err != nil {
+		err = Localize(err, LocaleFromRequest(req.Raw))
+		req.Throw(http.StatusBadRequest, err, r.Type.JSON)
+	}
+}
+
+func compileJSONNode(raw map[string]interface{}, at string) (*jsonNode, error) {
+	if _, ok := raw["$ref"]; ok {
+		return nil, fmt.Errorf("%s: $ref is not supported", at)
+	}
+
+	n := &jsonNode{}
+	switch t := raw["type"].(type) {
+	case nil:
+	case string:
+		n.types = []string{t}
+	case []interface{}:
+		for _, v := range t {
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: type must hold strings", at)
+			}
+			n.types = append(n.types, s)
+		}
+	default:
+		return nil, fmt.Errorf("%s: type must be a string or a list", at)
+	}
+
+	if enum, ok := raw["enum"].([]interface{}); ok {
+		n.enum = enum
+	}
+
+	ints := map[string]func(int) r.Assertion{
+		"minLength": StrMin, "maxLength": StrMax,
+		"minItems": SliceMin, "maxItems": SliceMax,
+	}
+	for _, key := range sortedKeys(ints) {
+		v, ok := raw[key]
+		if !ok {
+			continue
+		}
+
+		i, err := AsInt(v)
+		if err != nil || i < 0 {
+			return nil, fmt.Errorf("%s: %s must be a non-negative integer", at, key)
+		}
+
+		if key == "minLength" || key == "maxLength" {
+			n.strChecks = append(n.strChecks, ints[key](int(i)))
+		} else {
+			n.listChecks = append(n.listChecks, ints[key](int(i)))
+		}
+	}
+
+	if v, ok := raw["pattern"]; ok {
+		p, isStr := v.(string)
+		if !isStr {
+			return nil, fmt.Errorf("%s: pattern must be a string", at)
+		}
+		if _, err := compilePattern(p); err != nil {
+			return nil, fmt.Errorf("%s: %v", at, err)
+		}
+		n.strChecks = append(n.strChecks, StrMatches(p))
+	}
+
+	if v, ok := raw["format"].(string); ok {
+		if check, known := jsonFormats[v]; known {
+			n.strChecks = append(n.strChecks, check)
+		}
+	}
+
+	bounds := []struct {
+		key  string
+		code string
+		fail func(val, bound float64) bool
+		msg  string
+	}{
+		{"minimum", "num.min", func(v, b float64) bool { return v < b }, "at least"},
+		{"maximum", "num.max", func(v, b float64) bool { return v > b }, "at most"},
+		{"exclusiveMinimum", "num.exclusive_min", func(v, b float64) bool { return v <= b }, "greater than"},
+		{"exclusiveMaximum", "num.exclusive_max", func(v, b float64) bool { return v >= b }, "less than"},
+	}
+	for _, b := range bounds {
+		v, ok := raw[b.key]
+		if !ok {
+			continue
+		}
+
+		bound, err := AsFloat(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s must be a number", at, b.key)
+		}
+		n.numChecks = append(n.numChecks, numBound(bound, b.code, b.msg, b.fail))
+	}
+
+	if unique, _ := raw["uniqueItems"].(bool); unique {
+		n.listChecks = append(n.listChecks, SliceUnique)
+	}
+
+	if props, ok := raw["properties"].(map[string]interface{}); ok {
+		n.properties = make(map[string]*jsonNode, len(props))
+		for name, p := range props {
+			sub, ok := p.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s/properties/%s: must be an object", at, name)
+			}
+
+			child, err := compileJSONNode(sub, at+"/properties/"+name)
+			if err != nil {
+				return nil, err
+			}
+			n.properties[name] = child
+		}
+	}
+
+	if req, ok := raw["required"].([]interface{}); ok {
+		for _, v := range req {
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: required must hold strings", at)
+			}
+			n.required = append(n.required, s)
+		}
+	}
+
+	if additional, ok := raw["additionalProperties"].(bool); ok {
+		n.closed = !additional
+	}
+
+	if items, ok := raw["items"].(map[string]interface{}); ok {
+		child, err := compileJSONNode(items, at+"/items")
+		if err != nil {
+			return nil, err
+		}
+		n.items = child
+	}
+
+	return n, nil
+}
+
+// validate appends the errors of val to errs, path is the name of the
+// field val was found at
+func (n *jsonNode) validate(val interface{}, path string, errs *ValidationErrors) {
+	fail := func(err error) {
+		*errs = append(*errs, bindField(path, err)...)
+	}
+
+	kind := jsonType(val)
+	if len(n.types) > 0 && !matchesJSONType(kind, val, n.types) {
+		fail(errorf("json.type", Params{"types": n.types},
+			"$ must be of type %s", joinTypes(n.types)))
+		return
+	}
+
+	if len(n.enum) > 0 && !inEnum(val, n.enum) {
+		fail(errorf("enum", Params{"values": n.enum}, "$ must be one of %v", n.enum))
+		return
+	}
+
+	var checks []r.Assertion
+	switch kind {
+	case "string":
+		checks = n.strChecks
+	case "number":
+		checks = n.numChecks
+	case "array":
+		checks = n.listChecks
+	}
+
+	for _, check := range checks {
+		if err := check(val); err != nil {
+			fail(err)
+			return
+		}
+	}
+
+	switch v := val.(type) {
+	case map[string]interface{}:
+		n.validateObject(v, path, errs)
+	case []interface{}:
+		if n.items == nil {
+			return
+		}
+		for i, item := range v {
+			n.items.validate(item, path+"["+strconv.Itoa(i)+"]", errs)
+		}
+	}
+}
+
+func (n *jsonNode) validateObject(obj map[string]interface{}, path string,
+	errs *ValidationErrors) {
+	child := func(name string) string {
+		if path == "" {
+			return name
+		}
+		return path + "." + name
+	}
+
+	for _, name := range n.required {
+		if _, ok := obj[name]; !ok {
+			*errs = append(*errs, bindField(child(name), errorf("required", nil, "$ is required"))...)
+		}
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop, ok := n.properties[name]
+		switch {
+		case ok:
+			prop.validate(obj[name], child(name), errs)
+		case n.closed:
+			*errs = append(*errs, bindField(child(name),
+				errorf("json.additional", nil, "$ is not allowed"))...)
+		}
+	}
+}
+
+func numBound(bound float64, code, msg string, fail func(val, bound float64) bool) r.Assertion {
+	return func(val interface{}) error {
+		f, err := AsFloat(val)
+		if err != nil {
+			return err
+		}
+
+		if fail(f, bound) {
+			return errorf(code, Params{"bound": bound}, "$ must be %s %v", msg, bound)
+		}
+
+		return nil
+	}
+}
+
+// toJSONValue brings the body into the form json.Unmarshal produces with
+// numbers kept as json.Number
+func toJSONValue(body interface{}) (interface{}, error) {
+	switch body.(type) {
+	case nil, map[string]interface{}, []interface{}:
+		return body, nil
+	}
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var val interface{}
+	err = dec.Decode(&val)
+	return val, err
+}
+
+func jsonType(val interface{}) string {
+	switch val.(type) {
+	case nil:
+		return "null"
+	case json.Number:
+		return "number"
+	}
+
+	switch reflect.TypeOf(val).Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.String:
+		return "string"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	}
+
+	if _, err := AsFloat(val); err == nil {
+		return "number"
+	}
+
+	return "unknown"
+}
+
+func matchesJSONType(kind string, val interface{}, types []string) bool {
+	for _, t := range types {
+		if t == kind {
+			return true
+		}
+
+		if t == "integer" && kind == "number" {
+			if _, err := AsInt(val); err == nil {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func inEnum(val interface{}, enum []interface{}) bool {
+	for _, e := range enum {
+		if elemEqual(val, e) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func joinTypes(types []string) string {
+	if len(types) == 1 {
+		return types[0]
+	}
+
+	return fmt.Sprintf("%v", types)
+}
+
+func sortedKeys(m map[string]func(int) r.Assertion) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
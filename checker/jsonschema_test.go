@@ -0,0 +1,120 @@
+package checker
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const userSchema = `{
+	"type": "object",
+	"required": ["name", "age"],
+	"additionalProperties": false,
+	"properties": {
+		"name": {"type": "string", "minLength": 2, "maxLength": 5},
+		"age": {"type": "integer", "minimum": 18, "exclusiveMaximum": 130},
+		"role": {"enum": ["admin", "user"]},
+		"email": {"type": "string", "format": "email"},
+		"server": {"type": "string", "format": "ipv4"},
+		"peer": {"type": "string", "format": "ipv6"},
+		"tags": {"type": "array", "uniqueItems": true, "maxItems": 2,
+			"items": {"type": "string", "pattern": "^[a-z]+$"}},
+		"address": {"type": "object", "required": ["city"],
+			"properties": {"city": {"type": ["string", "null"]}}}
+	}
+}`
+
+func TestJSONSchemaValidate(t *testing.T) {
+	schema := MustCompileJSONSchema([]byte(userSchema))
+	tests := []struct {
+		name  string
+		body  string
+		field string
+		code  string
+	}{
+		{"valid", `{"name": "ann", "age": 30, "tags": ["a"], "address": {"city": null}}`, "", ""},
+		{"missing", `{"name": "ann"}`, "age", "required"},
+		{"additional", `{"name": "ann", "age": 30, "admin": true}`, "admin", "json.additional"},
+		{"type", `{"name": 3, "age": 30}`, "name", "json.type"},
+		{"integer", `{"name": "ann", "age": 30.5}`, "age", "json.type"},
+		{"integral float", `{"name": "ann", "age": 30.0}`, "", ""},
+		{"min length", `{"name": "a", "age": 30}`, "name", "str.min"},
+		{"minimum", `{"name": "ann", "age": 17}`, "age", "num.min"},
+		{"exclusive maximum", `{"name": "ann", "age": 130}`, "age", "num.exclusive_max"},
+		{"enum", `{"name": "ann", "age": 30, "role": "root"}`, "role", "enum"},
+		{"email", `{"name": "ann", "age": 30, "email": "ann"}`, "email", "email"},
+		{"ipv4", `{"name": "ann", "age": 30, "server": "10.0.0.1"}`, "", ""},
+		{"ipv4 rejects ipv6", `{"name": "ann", "age": 30, "server": "::1"}`, "server",
+			"format.ipv4"},
+		{"ipv4 rejects mapped", `{"name": "ann", "age": 30, "server": "::ffff:10.0.0.1"}`,
+			"server", "format.ipv4"},
+		{"ipv6", `{"name": "ann", "age": 30, "peer": "2001:db8::1"}`, "", ""},
+		{"ipv6 mapped", `{"name": "ann", "age": 30, "peer": "::ffff:10.0.0.1"}`, "", ""},
+		{"ipv6 rejects ipv4", `{"name": "ann", "age": 30, "peer": "10.0.0.1"}`, "peer",
+			"format.ipv6"},
+		{"unique items", `{"name": "ann", "age": 30, "tags": ["a", "a"]}`, "tags", "slice.unique"},
+		{"item pattern", `{"name": "ann", "age": 30, "tags": ["a", "B"]}`, "tags[1]",
+			"str.matches"},
+		{"nested required", `{"name": "ann", "age": 30, "address": {}}`, "address.city",
+			"required"},
+	}
+
+	for _, tt := range tests {
+		var body interface{}
+		dec := json.NewDecoder(strings.NewReader(tt.body))
+		dec.UseNumber()
+		if err := dec.Decode(&body); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		err := schema.Validate(body)
+		if tt.code == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tt.name, err)
+			}
+			continue
+		}
+
+		errs, ok := err.(ValidationErrors)
+		if !ok || len(errs) != 1 {
+			t.Errorf("%s: got %v, want one error", tt.name, err)
+			continue
+		}
+
+		if errs[0].Field != tt.field || errs[0].Code != tt.code {
+			t.Errorf("%s: got %s %s, want %s %s", tt.name, errs[0].Field, errs[0].Code,
+				tt.field, tt.code)
+		}
+	}
+}
+
+func TestJSONSchemaStruct(t *testing.T) {
+	schema := MustCompileJSONSchema([]byte(userSchema))
+	type user struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+
+	if err := schema.Validate(user{"ann", 30}); err != nil {
+		t.Errorf("valid struct: %v", err)
+	}
+	expectCode(t, "struct", schema.Validate(user{"ann", 12}), "num.min")
+}
+
+func TestCompileJSONSchemaErrors(t *testing.T) {
+	docs := []string{
+		`not json`,
+		`{"$ref": "#/definitions/user"}`,
+		`{"properties": {"a": {"$ref": "#"}}}`,
+		`{"type": 3}`,
+		`{"minLength": -1}`,
+		`{"pattern": "("}`,
+		`{"minimum": "low"}`,
+	}
+
+	for _, doc := range docs {
+		if _, err := CompileJSONSchema([]byte(doc)); err == nil {
+			t.Errorf("%s: compiled without error", doc)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package checker
+
+import (
+	"reflect"
+	"strings"
+
+	r "github.com/rubikorg/rubik"
+)
+
+// Rule describes what an assertion checks so that documentation and SDK
+// generators can show it. Keyword is a JSON Schema keyword like
+// minLength, enum, pattern or format and Value is its argument, i.e:
+// StrMin(3) is described as Rule{"minLength", 3}. The keywords min and
+// max are used by rules which check strings, lists and numbers alike and
+// notEmpty by Required, DescribeEntity turns them into the keyword for
+// the field type
+type Rule struct {
+	Keyword string      `json:"keyword"`
+	Value   interface{} `json:"value"`
+}
+
+// FieldRules are the rules of the fields of an entity keyed by the names
+// used in validation errors
+type FieldRules map[string][]Rule
+
+// ruleProbe is the value RulesOf passes to assertions, described
+// assertions add their rules to it instead of checking it
+type ruleProbe struct {
+	rules []Rule
+}
+
+// Describe returns an assertion which checks like assert and carries the
+// given rules, RulesOf reads them back. The assertions of this package
+// describe themselves, use it for custom ones, i.e:
+// Describe(isSlug, Rule{"pattern", "^[a-z0-9-]+$"})
+func Describe(assert r.Assertion, rules ...Rule) r.Assertion {
+	return func(val interface{}) error {
+		if describes(val, rules...) {
+			return nil
+		}
+
+		return assert(val)
+	}
+}
+
+// RulesOf returns the rules carried by the assertions. All and Optional
+// pass on the rules of the assertions they wrap while Any, Not and When
+// add none. Assertions which were not described add nothing, they are
+// called with a value they do not know and their errors and panics are
+// ignored
+func RulesOf(assertions ...r.Assertion) []Rule {
+	p := &ruleProbe{}
+	for _, assert := range assertions {
+		probeAssertion(assert, p)
+	}
+
+	return p.rules
+}
+
+// DescribeFields returns the rules of every field of a validation map
+// like the Validation of a rubik route. The keywords min, max and
+// notEmpty are resolved against the fields of entity, without an entity
+// min and max are kept and notEmpty is dropped
+func DescribeFields(entity interface{}, fields map[string][]r.Assertion) FieldRules {
+	docs := make(FieldRules, len(fields))
+	for name, assertions := range fields {
+		if rules := RulesOf(assertions...); len(rules) > 0 {
+			docs[name] = rules
+		}
+	}
+
+	return resolveFields(reflect.TypeOf(entity), docs)
+}
+
+// describes adds rules to val when it is the probe of RulesOf, described
+// assertions call it first and return nil when it reports true
+func describes(val interface{}, rules ...Rule) bool {
+	p, ok := val.(*ruleProbe)
+	if ok {
+		p.rules = append(p.rules, rules...)
+	}
+
+	return ok
+}
+
+func probeAssertion(assert r.Assertion, p *ruleProbe) {
+	defer func() {
+		recover()
+	}()
+
+	assert(p)
+}
+
+// RegisterSchema makes schema the schema of the type of entity, SchemaOf
+// and DescribeEntity return it instead of reading the `check` tags. Use
+// it for entities validated by a hand written Schema so that the docs
+// know about it
+func RegisterSchema(entity interface{}, schema Schema) {
+	rt := reflect.TypeOf(entity)
+	for rt != nil && rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+
+	if rt != nil {
+		tagSchemas.Store(rt, schema)
+	}
+}
+
+// DescribeEntity returns the rules of the fields of the registered
+// schema of the entity or of the schema built from its `check` tags,
+// i.e: for the swagger block. The rules are read from the assertions of
+// the schema with RulesOf, the Docs of the schema are added to them
+func DescribeEntity(entity interface{}) (FieldRules, error) {
+	schema, err := SchemaOf(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make(FieldRules, len(schema.Fields)+len(schema.Docs))
+	for name, assertions := range schema.Fields {
+		docs[name] = RulesOf(assertions...)
+	}
+	for name, rules := range schema.Docs {
+		docs[name] = append(docs[name], rules...)
+	}
+
+	return resolveFields(reflect.TypeOf(entity), docs), nil
+}
+
+// resolveFields resolves the rules of the fields against the struct type
+// rt, fields left without rules are dropped
+func resolveFields(rt reflect.Type, docs FieldRules) FieldRules {
+	fields := make(FieldRules, len(docs))
+	for name, rules := range docs {
+		ft := fieldType(rt, name)
+		// the length implied by notEmpty goes first so that an explicit
+		// min wins in Keywords
+		var implied, resolved []Rule
+		for _, rule := range rules {
+			res, ok := resolveRule(rule, ft)
+			switch {
+			case !ok:
+			case rule.Keyword == "notEmpty":
+				implied = append(implied, res)
+			default:
+				resolved = append(resolved, res)
+			}
+		}
+		resolved = append(implied, resolved...)
+
+		if len(resolved) > 0 {
+			fields[name] = resolved
+		}
+	}
+
+	return fields
+}
+
+// Lookup returns the rules of the first of the given names which has
+// rules, names are compared ignoring case when there is no exact match
+func (fr FieldRules) Lookup(names ...string) []Rule {
+	for _, name := range names {
+		if rules, ok := fr[name]; ok {
+			return rules
+		}
+	}
+
+	for _, name := range names {
+		for field, rules := range fr {
+			if strings.EqualFold(field, name) {
+				return rules
+			}
+		}
+	}
+
+	return nil
+}
+
+// Keywords collects the rules into a map of JSON Schema keywords, the
+// last rule wins when a keyword is repeated
+func Keywords(rules []Rule) map[string]interface{} {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	m := make(map[string]interface{}, len(rules))
+	for _, rule := range rules {
+		m[rule.Keyword] = rule.Value
+	}
+
+	return m
+}
+
+// resolveRule turns the min, max and notEmpty keywords into the keyword
+// matching the type of the field, notEmpty is dropped for fields which
+// have no length
+func resolveRule(rule Rule, ft reflect.Type) (Rule, bool) {
+	if rule.Keyword == "notEmpty" {
+		if ft == nil {
+			return rule, false
+		}
+
+		switch ft.Kind() {
+		case reflect.String:
+			return Rule{"minLength", 1}, true
+		case reflect.Slice, reflect.Array:
+			return Rule{"minItems", 1}, true
+		case reflect.Map:
+			return Rule{"minProperties", 1}, true
+		}
+		return rule, false
+	}
+
+	if ft == nil || (rule.Keyword != "min" && rule.Keyword != "max") {
+		return rule, true
+	}
+
+	suffix := "imum"
+	switch ft.Kind() {
+	case reflect.String:
+		suffix = "Length"
+	case reflect.Slice, reflect.Array:
+		suffix = "Items"
+	}
+
+	return Rule{rule.Keyword + suffix, rule.Value}, true
+}
+
+// fieldType finds the type of the field at the dot-notation path inside
+// the struct type rt, nil is returned for maps and unknown fields
+func fieldType(rt reflect.Type, path string) reflect.Type {
+	for _, part := range strings.Split(path, ".") {
+		for rt != nil && rt.Kind() == reflect.Ptr {
+			rt = rt.Elem()
+		}
+
+		if rt == nil || rt.Kind() != reflect.Struct {
+			return nil
+		}
+
+		rt = structFieldType(rt, part)
+	}
+
+	for rt != nil && rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+
+	return rt
+}
+
+func structFieldType(rt reflect.Type, name string) reflect.Type {
+	var embedded []reflect.Type
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if sf.Anonymous {
+			et := sf.Type
+			if et.Kind() == reflect.Ptr {
+				et = et.Elem()
+			}
+			if et.Kind() == reflect.Struct {
+				embedded = append(embedded, et)
+			}
+			continue
+		}
+
+		if sf.PkgPath == "" && (fieldName(sf) == name || strings.EqualFold(sf.Name, name)) {
+			return sf.Type
+		}
+	}
+
+	for _, et := range embedded {
+		if ft := structFieldType(et, name); ft != nil {
+			return ft
+		}
+	}
+
+	return nil
+}
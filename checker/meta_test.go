@@ -0,0 +1,158 @@
+package checker
+
+import (
+	"reflect"
+	"testing"
+
+	r "github.com/rubikorg/rubik"
+)
+
+type describedEntity struct {
+	Name    string   `json:"name" check:"required,min=3,max=20"`
+	Age     int      `json:"age" check:"min=18"`
+	Tags    []string `json:"tags" check:"max=5,unique"`
+	Role    string   `json:"role" check:"oneof=admin|user"`
+	Email   string   `json:"email" check:"email"`
+	Comment string   `json:"comment" check:"printable"`
+	Address struct {
+		Zip string `json:"zip" check:"min=5"`
+	} `json:"address"`
+}
+
+func TestDescribeEntity(t *testing.T) {
+	fields, err := DescribeEntity(describedEntity{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := FieldRules{
+		"name":        {{"minLength", 1}, {"required", true}, {"minLength", 3}, {"maxLength", 20}},
+		"age":         {{"minimum", 18}},
+		"tags":        {{"maxItems", 5}, {"uniqueItems", true}},
+		"role":        {{"enum", []string{"admin", "user"}}},
+		"email":       {{"format", "email"}},
+		"address.zip": {{"minLength", 5}},
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("got %v, want %v", fields, want)
+	}
+
+	if min := Keywords(fields["name"])["minLength"]; min != 3 {
+		t.Errorf("explicit min lost to required: minLength %v", min)
+	}
+}
+
+func TestDescribeRegisteredSchema(t *testing.T) {
+	type manual struct {
+		Code  string
+		Qty   int
+		Kind  string
+		Lines []string
+		Ref   string
+	}
+	RegisterSchema(manual{}, Schema{}.
+		Field("Code", Required, StrMatches(`^[A-Z]{3}$`, "code")).
+		Field("Qty", IntRange(1, 99)).
+		Field("Kind", Optional(StrIsOneOf("a", "b")), Any(IsEmail, IsURL)).
+		Field("Lines", SliceMax(3)).
+		Field("Ref", StrMin(2), Not(IsUUID)))
+
+	fields, err := DescribeEntity(&manual{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := FieldRules{
+		"Code":  {{"minLength", 1}, {"required", true}, {"pattern", `^[A-Z]{3}$`}},
+		"Qty":   {{"minimum", 1}, {"maximum", 99}},
+		"Kind":  {{"enum", []string{"a", "b"}}},
+		"Lines": {{"maxItems", 3}},
+		"Ref":   {{"minLength", 2}},
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("got %v, want %v", fields, want)
+	}
+
+	if got := fields.Lookup("code"); len(got) != 3 {
+		t.Errorf("lookup ignoring case: got %v", got)
+	}
+}
+
+func TestRulesOf(t *testing.T) {
+	slug := Describe(StrMatches("^[a-z-]+$"), Rule{"pattern", "^[a-z-]+$"})
+	custom := func(val interface{}) error { return StrBoolIsTrue(val) }
+	panics := func(val interface{}) error { return val.(error) }
+
+	got := RulesOf(slug, StrMaxGraphemes(10), Alpha, custom, panics, FloatRange(0.5, 2))
+	want := []Rule{{"pattern", "^[a-z-]+$"}, {"maxLength", 10}, {"minimum", 0.5}, {"maximum", 2.0}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if err := slug("Not A Slug"); err == nil {
+		t.Error("the described assertion stopped checking")
+	}
+
+	if err := RegisterPattern("sku", `^\d+$`); err != nil {
+		t.Fatal(err)
+	}
+	if got := RulesOf(StrPattern("sku")); !reflect.DeepEqual(got, []Rule{{"pattern", `^\d+$`}}) {
+		t.Errorf("pattern: got %v", got)
+	}
+	if got := RulesOf(StrPattern("nosuch")); got != nil {
+		t.Errorf("unknown pattern: got %v", got)
+	}
+}
+
+func TestDescribeFields(t *testing.T) {
+	if err := RegisterAssertion("slug", StrMatches("^[a-z-]+$"), Rule{"pattern", "^[a-z-]+$"}); err != nil {
+		t.Fatal(err)
+	}
+	slug, err := ParseRules("required,slug,max=40")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validation := map[string][]r.Assertion{
+		"slug":  slug,
+		"tags":  {Each(Alpha), SliceUnique},
+		"notes": {IsStr},
+	}
+
+	want := FieldRules{
+		"slug": {{"required", true}, {"pattern", "^[a-z-]+$"}, {"max", 40}},
+		"tags": {{"uniqueItems", true}},
+	}
+	if got := DescribeFields(nil, validation); !reflect.DeepEqual(got, want) {
+		t.Errorf("without entity: got %v, want %v", got, want)
+	}
+
+	var entity struct {
+		Slug string   `json:"slug"`
+		Tags []string `json:"tags"`
+	}
+	want["slug"] = []Rule{{"minLength", 1}, {"required", true}, {"pattern", "^[a-z-]+$"}, {"maxLength", 40}}
+	if got := DescribeFields(entity, validation); !reflect.DeepEqual(got, want) {
+		t.Errorf("with entity: got %v, want %v", got, want)
+	}
+}
+
+func TestConfigSchemaDocs(t *testing.T) {
+	schemas, err := parseConfigSchemas(map[string]interface{}{
+		"signup": map[string]interface{}{
+			"email":    []interface{}{"required", "email"},
+			"username": "min=3,max=20",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := FieldRules{
+		"email":    {{"required", true}, {"format", "email"}},
+		"username": {{"min", 3}, {"max", 20}},
+	}
+	if got := DescribeFields(nil, schemas["signup"].Fields); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,74 @@
+package checker
+
+import (
+	"reflect"
+	"testing"
+
+	r "github.com/rubikorg/rubik"
+)
+
+type address struct {
+	City string `json:"city"`
+	Zip  string `json:"zip"`
+}
+
+type item struct {
+	SKU string `json:"sku"`
+}
+
+type order struct {
+	Address *address `json:"address"`
+	Tags    []string `json:"tags"`
+	Items   []item   `json:"items"`
+}
+
+func TestNestedPaths(t *testing.T) {
+	city := Schema{Fields: map[string][]r.Assertion{"city": {StrMin(2)}}}
+	sku := Schema{Fields: map[string][]r.Assertion{"sku": {StrMin(3)}}}
+	schema := Schema{Fields: map[string][]r.Assertion{
+		"address":     {Nested(city)},
+		"address.zip": {StrMin(5)},
+		"tags":        {Each(StrMin(2))},
+		"items":       {Each(Nested(sku))},
+	}}
+
+	tests := []struct {
+		name   string
+		entity interface{}
+		fields []string
+	}{
+		{"struct", order{
+			Address: &address{City: "X", Zip: "123"},
+			Tags:    []string{"go", "js", "c"},
+			Items:   []item{{SKU: "a"}, {SKU: "abc"}},
+		}, []string{"address.city", "address.zip", "items[0].sku", "tags[2]"}},
+		{"struct passes", order{
+			Address: &address{City: "Rome", Zip: "00100"},
+			Tags:    []string{"go"},
+			Items:   []item{{SKU: "abc"}},
+		}, nil},
+		{"struct without parent", order{Tags: []string{"c"}}, []string{"tags[0]"}},
+		{"map", map[string]interface{}{
+			"address": map[string]interface{}{"city": "X", "zip": "00100"},
+			"items":   []interface{}{map[string]interface{}{"sku": "abc"}, map[string]interface{}{"sku": "b"}},
+		}, []string{"address.city", "items[1].sku"}},
+	}
+
+	for _, tt := range tests {
+		var fields []string
+		if errs, ok := schema.Validate(tt.entity).(ValidationErrors); ok {
+			for _, ve := range errs {
+				fields = append(fields, ve.Field)
+			}
+		}
+
+		if !reflect.DeepEqual(fields, tt.fields) {
+			t.Errorf("%s: got fields %v, want %v", tt.name, fields, tt.fields)
+		}
+	}
+
+	err := schema.Validate(order{Items: []item{{SKU: "a"}}}).(ValidationErrors)[0]
+	if want := "minimum 3 characters needed but value: items[0].sku"; err.Error() != want {
+		t.Errorf("got %q, want %q", err.Error(), want)
+	}
+}
@@ -0,0 +1,258 @@
+package checker
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	r "github.com/rubikorg/rubik"
+)
+
+// PasswordOptions configures PasswordStrength
+type PasswordOptions struct {
+	// MinLength is counted in characters
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// MinEntropy is the least estimated entropy in bits, see
+	// PasswordEntropy
+	MinEntropy float64
+	// AllowCommon skips the check against the common password list
+	AllowCommon bool
+}
+
+// DefaultPasswordOptions asks for 10 characters from at least lower and
+// upper case letters and digits with about 50 bits of entropy
+var DefaultPasswordOptions = PasswordOptions{
+	MinLength:    10,
+	RequireUpper: true,
+	RequireLower: true,
+	RequireDigit: true,
+	MinEntropy:   50,
+}
+
+// commonPasswords holds the most used passwords of public breach
+// corpora in lower case, AddCommonPasswords extends it
+var commonPasswords = struct {
+	sync.RWMutex
+	m map[string]bool
+}{m: setOf(
+	"123456", "password", "12345678", "qwerty", "123456789", "12345",
+	"1234", "111111", "1234567", "dragon", "123123", "baseball", "abc123",
+	"football", "monkey", "letmein", "696969", "shadow", "master", "666666",
+	"qwertyuiop", "123321", "mustang", "1234567890", "michael", "654321",
+	"superman", "1qaz2wsx", "7777777", "121212", "000000", "qazwsx",
+	"123qwe", "killer", "trustno1", "jordan", "jennifer", "zxcvbnm",
+	"asdfgh", "hunter", "buster", "soccer", "harley", "batman", "andrew",
+	"tigger", "sunshine", "iloveyou", "2000", "charlie", "robert",
+	"thomas", "hockey", "ranger", "daniel", "starwars",
+	"112233", "george", "computer", "michelle", "jessica", "pepper",
+	"1111", "zxcvbn", "555555", "11111111", "131313", "freedom", "777777",
+	"pass", "maggie", "159753", "aaaaaa", "ginger", "princess", "joshua",
+	"cheese", "amanda", "summer", "love", "ashley", "nicole", "chelsea",
+	"biteme", "matthew", "access", "yankees", "987654321", "dallas",
+	"austin", "thunder", "taylor", "matrix", "password1",
+	"password123", "passw0rd", "p@ssw0rd", "p@ssword", "welcome",
+	"welcome1", "admin", "admin123", "administrator", "root", "toor",
+	"qwerty123", "qwerty1", "1q2w3e4r", "1q2w3e4r5t", "q1w2e3r4",
+	"zaq12wsx", "123abc", "abcd1234", "aa123456", "a123456", "000000000",
+	"123654", "1qazxsw2", "football1", "baseball1", "iloveyou1",
+	"princess1", "sunshine1", "letmein1", "changeme", "secret", "default",
+	"guest", "login", "hello", "hello123", "whatever", "qwertyui",
+	"asdfghjkl", "google", "internet", "samsung", "apple", "linkedin",
+	"facebook", "starwars1", "pokemon", "superman1", "batman1",
+	"dragon1", "master1", "michael1", "shadow1", "monkey1", "liverpool",
+	"arsenal", "chelsea1", "blink182", "myspace1", "flower", "lovely",
+	"loveme", "babygirl", "angel", "jesus", "jesus1", "qwe123", "azerty",
+	"1234qwer", "passpass", "test", "test123", "testing", "demo",
+)}
+
+// AddCommonPasswords extends the list of passwords rejected by
+// PasswordStrength, i.e: with the passwords of a breach corpus
+func AddCommonPasswords(passwords ...string) {
+	commonPasswords.Lock()
+	for _, p := range passwords {
+		commonPasswords.m[strings.ToLower(p)] = true
+	}
+	commonPasswords.Unlock()
+}
+
+// PasswordStrength checks the value against the options. A weak password
+// is reported with a single password.weak error whose `problems` param
+// lists the failed checks (length, upper, lower, digit, symbol, entropy,
+// common) and whose `feedback` param holds hints like "add a symbol" for
+// the signup form
+func PasswordStrength(opts PasswordOptions) r.Assertion {
+	return func(val interface{}) error {
+		return checkPassword(val, opts, nil)
+	}
+}
+
+// PasswordFor is the entity-aware form of PasswordStrength. Besides the
+// options it rejects passwords which contain the value of one of the
+// personal fields, reported as the `personal` problem. The local part of
+// email addresses is compared too, i.e:
+// PasswordFor("password", DefaultPasswordOptions, "username", "email")
+func PasswordFor(field string, opts PasswordOptions, personal ...string) EntityRule {
+	return func(entity interface{}) error {
+		val, _ := lookupField(entity, field)
+
+		var words []string
+		for _, name := range personal {
+			v, _ := lookupField(entity, name)
+			if v == nil || IsStr(v) != nil {
+				continue
+			}
+
+			s := strings.ToLower(strings.TrimSpace(strValue(v)))
+			words = append(words, s)
+			if at := strings.LastIndex(s, "@"); at > 0 {
+				words = append(words, s[:at])
+			}
+		}
+
+		err := checkPassword(val, opts, words)
+		if err == nil {
+			return nil
+		}
+
+		return bindField(field, err)
+	}
+}
+
+// PasswordEntropy estimates the entropy of a password in bits from the
+// size of the character classes it uses. Characters repeating or
+// continuing a sequence of the previous one like `aaa` or `1234` do not
+// add to the estimate
+func PasswordEntropy(password string) float64 {
+	pool := 0
+	var lower, upper, digit, symbol, other bool
+	length := 0
+	var prev rune
+	for i, c := range password {
+		switch {
+		case c < utf8.RuneSelf && unicode.IsLower(c):
+			lower = true
+		case c < utf8.RuneSelf && unicode.IsUpper(c):
+			upper = true
+		case c < utf8.RuneSelf && unicode.IsDigit(c):
+			digit = true
+		case c < utf8.RuneSelf:
+			symbol = true
+		default:
+			other = true
+		}
+
+		if i == 0 || (c != prev && c != prev+1 && c != prev-1) {
+			length++
+		}
+		prev = c
+	}
+
+	for _, class := range []struct {
+		used bool
+		size int
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {symbol, 33}, {other, 100}} {
+		if class.used {
+			pool += class.size
+		}
+	}
+
+	if pool == 0 {
+		return 0
+	}
+
+	return float64(length) * math.Log2(float64(pool))
+}
+
+func checkPassword(val interface{}, opts PasswordOptions, personal []string) error {
+	err := IsStr(val)
+	if err != nil || val == nil {
+		return err
+	}
+
+	password := strValue(val)
+	var problems, feedback []string
+	fail := func(problem, hint string) {
+		problems = append(problems, problem)
+		feedback = append(feedback, hint)
+	}
+
+	if utf8.RuneCountInString(password) < opts.MinLength {
+		fail("length", "use at least "+strconv.Itoa(opts.MinLength)+" characters")
+	}
+
+	var lower, upper, digit, symbol bool
+	for _, c := range password {
+		switch {
+		case unicode.IsLower(c):
+			lower = true
+		case unicode.IsUpper(c):
+			upper = true
+		case unicode.IsDigit(c):
+			digit = true
+		case !unicode.IsSpace(c) && !unicode.IsLetter(c):
+			symbol = true
+		}
+	}
+
+	if opts.RequireUpper && !upper {
+		fail("upper", "add an uppercase letter")
+	}
+	if opts.RequireLower && !lower {
+		fail("lower", "add a lowercase letter")
+	}
+	if opts.RequireDigit && !digit {
+		fail("digit", "add a digit")
+	}
+	if opts.RequireSymbol && !symbol {
+		fail("symbol", "add a symbol")
+	}
+
+	entropy := PasswordEntropy(password)
+	if entropy < opts.MinEntropy {
+		fail("entropy", "make it longer or less predictable")
+	}
+
+	lowered := strings.ToLower(password)
+	if !opts.AllowCommon && isCommonPassword(lowered) {
+		fail("common", "avoid commonly used passwords")
+	}
+
+	for _, word := range personal {
+		if utf8.RuneCountInString(word) >= 3 && strings.Contains(lowered, word) {
+			fail("personal", "avoid using your name or email")
+			break
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return errorf("password.weak", Params{
+		"problems": problems,
+		"feedback": feedback,
+		"entropy":  math.Floor(entropy),
+	}, "$ is too weak: %s", strings.Join(feedback, ", "))
+}
+
+func isCommonPassword(lowered string) bool {
+	commonPasswords.RLock()
+	defer commonPasswords.RUnlock()
+	return commonPasswords.m[lowered]
+}
+
+func setOf(values ...string) map[string]bool {
+	m := make(map[string]bool, len(values))
+	for _, v := range values {
+		m[v] = true
+	}
+
+	return m
+}
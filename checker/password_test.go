@@ -0,0 +1,72 @@
+package checker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPasswordStrength(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		problems []string
+	}{
+		{"strong", "Violet-Harbor-42", nil},
+		{"short", "Vh4!x", []string{"length", "entropy"}},
+		{"lower case only", "violetharborwinter", []string{"upper", "digit"}},
+		{"common", "Password123", []string{"entropy", "common"}},
+		{"sequence", "Abcdefghij1234", []string{"entropy"}},
+	}
+
+	for _, tt := range tests {
+		err := PasswordStrength(DefaultPasswordOptions)(tt.password)
+		if got := problemsOf(err); !reflect.DeepEqual(got, tt.problems) {
+			t.Errorf("%s: got problems %v (%v), want %v", tt.name, got, err, tt.problems)
+		}
+	}
+}
+
+func TestPasswordFor(t *testing.T) {
+	rule := PasswordFor("password", DefaultPasswordOptions, "username", "email")
+	tests := []struct {
+		name     string
+		entity   interface{}
+		problems []string
+	}{
+		{"unrelated", map[string]interface{}{
+			"username": "jsmith", "email": "john@example.com", "password": "Violet-Harbor-42",
+		}, nil},
+		{"username", map[string]interface{}{
+			"username": "JSmith", "email": "john@example.com", "password": "Xq7-jsmith-Harbor",
+		}, []string{"personal"}},
+		{"email local part", struct {
+			Username string `json:"username"`
+			Email    string `json:"email"`
+			Password string `json:"password"`
+		}{"js", "Johnny.Appleseed@example.com", "My-johnny.appleseed-99"}, []string{"personal"}},
+		{"short username is ignored", map[string]interface{}{
+			"username": "vi", "password": "Violet-Harbor-42",
+		}, nil},
+		{"missing personal fields", map[string]interface{}{"password": "Violet-Harbor-42"}, nil},
+	}
+
+	for _, tt := range tests {
+		err := rule(tt.entity)
+		if got := problemsOf(err); !reflect.DeepEqual(got, tt.problems) {
+			t.Errorf("%s: got problems %v (%v), want %v", tt.name, got, err, tt.problems)
+		}
+
+		if errs, ok := err.(ValidationErrors); ok && errs[0].Field != "password" {
+			t.Errorf("%s: got field %q, want password", tt.name, errs[0].Field)
+		}
+	}
+}
+
+func problemsOf(err error) []string {
+	errs := bindField("", err)
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs[0].Params["problems"].([]string)
+}
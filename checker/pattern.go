@@ -0,0 +1,135 @@
+package checker
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+
+	r "github.com/rubikorg/rubik"
+)
+
+// regexCache keeps compiled expressions so that the same pattern used by
+// many assertions is only compiled once
+var regexCache = struct {
+	sync.RWMutex
+	m map[string]*regexp.Regexp
+}{m: make(map[string]*regexp.Regexp)}
+
+// namedPatterns is the registry used by StrPattern
+var namedPatterns = struct {
+	sync.RWMutex
+	m map[string]*regexp.Regexp
+}{m: make(map[string]*regexp.Regexp)}
+
+// StrMatches checks if the string value of the request field matches the
+// given regular expression. Anchor the expression with ^ and $ if the
+// whole value must match. Like regexp.MustCompile it panics if the
+// pattern does not compile, which happens when the assertion is created
+// and not while serving a request. The regex is never shown to the
+// client, pass a friendly name to report the value like StrPattern does,
+// i.e: StrMatches(`^[A-Z]{3}-\d{4}$`, "SKU")
+func StrMatches(pattern string, name ...string) r.Assertion {
+	re, err := compilePattern(pattern)
+	if err != nil {
+		panic(err)
+	}
+
+	friendly := ""
+	if len(name) > 0 {
+		friendly = name[0]
+	}
+
+	return func(val interface{}) error {
+		if describes(val, Rule{"pattern", re.String()}) {
+			return nil
+		}
+
+		err := IsStr(val)
+		if err != nil || val == nil {
+			return err
+		}
+
+		if re.MatchString(strValue(val)) {
+			return nil
+		}
+
+		if friendly != "" {
+			return errorf("str.pattern", Params{"name": friendly},
+				"$ must be a valid %s", friendly)
+		}
+
+		return errorf("str.matches", nil, "$ does not have the expected format")
+	}
+}
+
+// RegisterPattern compiles the given regular expression and registers it
+// under name to be used with StrPattern. Registering an existing name
+// replaces its pattern
+func RegisterPattern(name, pattern string) error {
+	re, err := compilePattern(pattern)
+	if err != nil {
+		return fmt.Errorf("checker: cannot register pattern %s: %v", name, err)
+	}
+
+	namedPatterns.Lock()
+	namedPatterns.m[name] = re
+	namedPatterns.Unlock()
+	return nil
+}
+
+// StrPattern checks if the string value of the request field matches the
+// pattern registered with the given name. The pattern is looked up when
+// the value is checked, so the assertion can be created before the
+// pattern is registered. The `pattern` rule of tags and the config
+// needs the pattern to be registered before the schema is built
+func StrPattern(name string) r.Assertion {
+	return func(val interface{}) error {
+		namedPatterns.RLock()
+		re, ok := namedPatterns.m[name]
+		namedPatterns.RUnlock()
+
+		// patterns registered later are described once they are known
+		var rules []Rule
+		if ok {
+			rules = append(rules, Rule{"pattern", re.String()})
+		}
+		if describes(val, rules...) {
+			return nil
+		}
+
+		err := IsStr(val)
+		if err != nil || val == nil {
+			return err
+		}
+
+		if !ok {
+			return errorf("str.pattern_unknown", Params{"name": name},
+				"$ cannot be checked, pattern %s is not registered", name)
+		}
+
+		if !re.MatchString(strValue(val)) {
+			return errorf("str.pattern", Params{"name": name}, "$ must be a valid %s", name)
+		}
+
+		return nil
+	}
+}
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	regexCache.RLock()
+	re, ok := regexCache.m[pattern]
+	regexCache.RUnlock()
+	if ok {
+		return re, nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	regexCache.Lock()
+	regexCache.m[pattern] = re
+	regexCache.Unlock()
+	return re, nil
+}
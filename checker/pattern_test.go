@@ -0,0 +1,66 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStrMatches(t *testing.T) {
+	sku := `^[A-Z]{3}-\d{4}$`
+	expectCode(t, "match", StrMatches(sku)("ABC-1234"), "")
+	expectCode(t, "nil", StrMatches(sku)(nil), "")
+
+	err := StrMatches(sku)("abc")
+	expectCode(t, "no match", err, "str.matches")
+	if strings.Contains(err.Error(), sku) {
+		t.Errorf("error %q shows the regex", err)
+	}
+
+	err = StrMatches(sku, "SKU")("abc")
+	expectCode(t, "named", err, "str.pattern")
+	if err.Error() != "$ must be a valid SKU" {
+		t.Errorf("named: got message %q", err)
+	}
+}
+
+func TestStrPattern(t *testing.T) {
+	if err := RegisterPattern("broken", "("); err == nil {
+		t.Error("a pattern which does not compile was registered")
+	}
+
+	namedPatterns.Lock()
+	delete(namedPatterns.m, "zip")
+	namedPatterns.Unlock()
+
+	check := StrPattern("zip")
+	expectCode(t, "unknown", check("12345"), "str.pattern_unknown")
+
+	if err := RegisterPattern("zip", `^\d{5}$`); err != nil {
+		t.Fatal(err)
+	}
+	expectCode(t, "match", check("12345"), "")
+	expectCode(t, "no match", check("1234"), "str.pattern")
+}
+
+func TestPatternRule(t *testing.T) {
+	if _, err := ParseRules("required,pattern=nosuch"); err == nil {
+		t.Error("a rule with an unregistered pattern was parsed")
+	}
+
+	type product struct {
+		Code string `check:"pattern=nosuch"`
+	}
+	if _, err := SchemaOf(product{}); err == nil {
+		t.Error("a tag with an unregistered pattern was compiled")
+	}
+
+	if err := RegisterPattern("sku-code", `^[A-Z]{3}-\d{4}$`); err != nil {
+		t.Fatal(err)
+	}
+
+	assertions, err := ParseRules("pattern=sku-code")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectCode(t, "registered", assertions[0]("abc"), "str.pattern")
+}
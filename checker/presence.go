@@ -0,0 +1,114 @@
+package checker
+
+import "fmt"
+
+// Required checks if the field is present and not empty. Unlike
+// MustExist an empty string, list or map fails as well, an absent field
+// is reported with the code required and an empty one with the code
+// required.empty. Zero numbers and false are values and pass. It is the
+// `required` rule of tags and the config, the `present` rule only checks
+// for nil like MustExist
+func Required(val interface{}) error {
+	if describes(val, Rule{"required", true}, Rule{"notEmpty", true}) {
+		return nil
+	}
+
+	if val == nil {
+		return errorf("required", nil, "$ is required")
+	}
+
+	if isEmpty(val) {
+		return errorf("required.empty", nil, "$ must not be empty")
+	}
+
+	return nil
+}
+
+// Forbidden checks if the field is absent. A field sent with an empty
+// value is present and fails, with struct entities use pointer fields
+// since a plain field always holds a value
+func Forbidden(val interface{}) error {
+	if val != nil {
+		return errorf("forbidden", nil, "$ is not allowed")
+	}
+
+	return nil
+}
+
+// RequiredIf requires field when the value of other equals value, i.e:
+// RequiredIf("company", "account_type", "business")
+func RequiredIf(field, other string, value interface{}) EntityRule {
+	return func(entity interface{}) error {
+		if !fieldEquals(entity, other, value) {
+			return nil
+		}
+
+		return requiredField(entity, field, "required_if",
+			Params{"other": other, "value": value},
+			fmt.Sprintf("$ is required when %s is %v", other, value))
+	}
+}
+
+// RequiredUnless requires field unless the value of other equals value,
+// i.e: RequiredUnless("email", "contact", "phone")
+func RequiredUnless(field, other string, value interface{}) EntityRule {
+	return func(entity interface{}) error {
+		if fieldEquals(entity, other, value) {
+			return nil
+		}
+
+		return requiredField(entity, field, "required_unless",
+			Params{"other": other, "value": value},
+			fmt.Sprintf("$ is required unless %s is %v", other, value))
+	}
+}
+
+// RequiredWith requires field when any of the others is present and not
+// empty, i.e: RequiredWith("zip", "street", "city")
+func RequiredWith(field string, others ...string) EntityRule {
+	return func(entity interface{}) error {
+		for _, other := range others {
+			if val, _ := lookupField(entity, other); Required(val) == nil {
+				return requiredField(entity, field, "required_with",
+					Params{"other": other},
+					fmt.Sprintf("$ is required with %s", other))
+			}
+		}
+
+		return nil
+	}
+}
+
+// ForbiddenIf rejects field when the value of other equals value, the
+// field must then be absent, i.e: ForbiddenIf("discount", "plan", "free")
+func ForbiddenIf(field, other string, value interface{}) EntityRule {
+	return func(entity interface{}) error {
+		if !fieldEquals(entity, other, value) {
+			return nil
+		}
+
+		if val, _ := lookupField(entity, field); val != nil {
+			return fieldErrorf(field, "forbidden_if", Params{"other": other, "value": value},
+				"$ is not allowed when %s is %v", other, value)
+		}
+
+		return nil
+	}
+}
+
+// requiredField reports field with the given code when it is absent or
+// empty, the empty param tells the two apart
+func requiredField(entity interface{}, field, code string, params Params, msg string) error {
+	val, _ := lookupField(entity, field)
+	if Required(val) == nil {
+		return nil
+	}
+
+	params["empty"] = val != nil
+	return fieldErrorf(field, code, params, "%s", msg)
+}
+
+func fieldEquals(entity interface{}, field string, value interface{}) bool {
+	val, _ := lookupField(entity, field)
+	return val != nil && elemEqual(val, value)
+}
@@ -0,0 +1,86 @@
+package checker
+
+import "testing"
+
+func TestRequiredRule(t *testing.T) {
+	type signup struct {
+		Email string   `json:"email" check:"required"`
+		Tags  []string `json:"tags" check:"required"`
+		Age   *int     `json:"age" check:"required"`
+		Note  *string  `json:"note" check:"present"`
+	}
+
+	age, empty := 0, ""
+	tests := []struct {
+		name   string
+		entity signup
+		field  string
+		code   string
+	}{
+		{"valid", signup{"a@b.co", []string{"x"}, &age, &empty}, "", ""},
+		{"empty string", signup{"", []string{"x"}, &age, &empty}, "email", "required.empty"},
+		{"empty list", signup{"a@b.co", []string{}, &age, &empty}, "tags", "required.empty"},
+		{"absent number", signup{"a@b.co", []string{"x"}, nil, &empty}, "age", "required"},
+		{"absent present", signup{"a@b.co", []string{"x"}, &age, nil}, "note", "required"},
+	}
+
+	for _, tt := range tests {
+		err := ValidateStruct(tt.entity)
+		if tt.code == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tt.name, err)
+			}
+			continue
+		}
+
+		errs, ok := err.(ValidationErrors)
+		if !ok || len(errs) != 1 || errs[0].Field != tt.field || errs[0].Code != tt.code {
+			t.Errorf("%s: got %v, want %s on %s", tt.name, err, tt.code, tt.field)
+		}
+	}
+}
+
+func TestRequiredConfigRule(t *testing.T) {
+	schemas, err := parseConfigSchemas(map[string]interface{}{
+		"signup": map[string]interface{}{"email": []interface{}{"required", "email"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema := schemas["signup"]
+	expectCode(t, "empty", schema.Validate(map[string]interface{}{"email": ""}), "required.empty")
+	expectCode(t, "absent", schema.Validate(map[string]interface{}{}), "required")
+	expectCode(t, "valid", schema.Validate(map[string]interface{}{"email": "a@b.co"}), "")
+}
+
+func TestConditionalPresence(t *testing.T) {
+	account := func(kind, company string) map[string]interface{} {
+		m := map[string]interface{}{"account_type": kind}
+		if company != "-" {
+			m["company"] = company
+		}
+		return m
+	}
+
+	requiredIf := RequiredIf("company", "account_type", "business")
+	expectCode(t, "if absent", requiredIf(account("business", "-")), "required_if")
+	expectCode(t, "if empty", requiredIf(account("business", "")), "required_if")
+	expectCode(t, "if other value", requiredIf(account("personal", "-")), "")
+	expectCode(t, "if present", requiredIf(account("business", "acme")), "")
+
+	unless := RequiredUnless("company", "account_type", "personal")
+	expectCode(t, "unless", unless(account("business", "-")), "required_unless")
+	expectCode(t, "unless matched", unless(account("personal", "-")), "")
+
+	with := RequiredWith("zip", "street", "city")
+	expectCode(t, "with", with(map[string]interface{}{"city": "Oslo"}), "required_with")
+	expectCode(t, "with empty other", with(map[string]interface{}{"city": ""}), "")
+	expectCode(t, "with all", with(map[string]interface{}{"city": "Oslo", "zip": "0150"}), "")
+
+	forbiddenIf := ForbiddenIf("discount", "plan", "free")
+	expectCode(t, "forbidden", forbiddenIf(map[string]interface{}{"plan": "free", "discount": ""}),
+		"forbidden_if")
+	expectCode(t, "forbidden absent", forbiddenIf(map[string]interface{}{"plan": "free"}), "")
+	expectCode(t, "forbidden other", forbiddenIf(map[string]interface{}{"plan": "pro", "discount": 5}), "")
+}
@@ -0,0 +1,149 @@
+package checker
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+// punycode parameters as described in RFC 3492
+const (
+	punyBase        = 36
+	punyTMin        = 1
+	punyTMax        = 26
+	punySkew        = 38
+	punyDamp        = 700
+	punyInitialBias = 72
+	punyInitialN    = 128
+	punyPrefix      = "xn--"
+)
+
+// toASCII converts an internationalized domain name into its ASCII
+// compatible form by punycode encoding every label that has non-ASCII
+// characters in it. Labels are lowercased before encoding, full nameprep
+// mapping is out of scope here.
+func toASCII(domain string) (string, error) {
+	labels := strings.Split(domain, ".")
+	for i, label := range labels {
+		if isASCII(label) {
+			labels[i] = strings.ToLower(label)
+			continue
+		}
+
+		if !utf8.ValidString(label) {
+			return "", errors.New("domain is not a valid UTF-8 string")
+		}
+
+		encoded, err := punyEncode(strings.ToLower(label))
+		if err != nil {
+			return "", err
+		}
+		labels[i] = punyPrefix + encoded
+	}
+
+	return strings.Join(labels, "."), nil
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+
+	return true
+}
+
+func punyEncode(s string) (string, error) {
+	runes := []rune(s)
+	var out []byte
+	for _, r := range runes {
+		if r < utf8.RuneSelf {
+			out = append(out, byte(r))
+		}
+	}
+
+	basic := len(out)
+	handled := basic
+	if basic > 0 {
+		out = append(out, '-')
+	}
+
+	n, bias, delta := punyInitialN, punyInitialBias, 0
+	for handled < len(runes) {
+		// find the smallest code point which is not yet handled
+		m := int(utf8.MaxRune) + 1
+		for _, r := range runes {
+			if int(r) >= n && int(r) < m {
+				m = int(r)
+			}
+		}
+
+		if (m - n) > (int(^uint32(0)>>1)-delta)/(handled+1) {
+			return "", errors.New("punycode overflow while encoding domain")
+		}
+		delta += (m - n) * (handled + 1)
+		n = m
+
+		for _, r := range runes {
+			if int(r) < n {
+				delta++
+			}
+
+			if int(r) != n {
+				continue
+			}
+
+			q := delta
+			for k := punyBase; ; k += punyBase {
+				t := k - bias
+				if t < punyTMin {
+					t = punyTMin
+				} else if t > punyTMax {
+					t = punyTMax
+				}
+
+				if q < t {
+					break
+				}
+				out = append(out, punyDigit(t+(q-t)%(punyBase-t)))
+				q = (q - t) / (punyBase - t)
+			}
+
+			out = append(out, punyDigit(q))
+			bias = punyAdapt(delta, handled+1, handled == basic)
+			delta = 0
+			handled++
+		}
+
+		delta++
+		n++
+	}
+
+	return string(out), nil
+}
+
+func punyAdapt(delta, numPoints int, first bool) int {
+	if first {
+		delta /= punyDamp
+	} else {
+		delta /= 2
+	}
+
+	delta += delta / numPoints
+	k := 0
+	for delta > ((punyBase-punyTMin)*punyTMax)/2 {
+		delta /= punyBase - punyTMin
+		k += punyBase
+	}
+
+	return k + (punyBase-punyTMin+1)*delta/(delta+punySkew)
+}
+
+func punyDigit(d int) byte {
+	if d < 26 {
+		return byte('a' + d)
+	}
+
+	return byte('0' + d - 26)
+}
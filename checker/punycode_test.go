@@ -0,0 +1,74 @@
+package checker
+
+import "testing"
+
+// punycodeVectors are the samples of RFC 3492 section 7.1, the upper case
+// letters the RFC uses as case annotations are lowered since the encoder
+// does not produce them
+var punycodeVectors = []struct {
+	name    string
+	input   string
+	encoded string
+}{
+	{"arabic", "ليهمابتكلموشعربي؟", "egbpdaj6bu4bxfgehfvwxn"},
+	{"chinese simplified", "他们为什么不说中文", "ihqwcrb4cv8a8dqg056pqjye"},
+	{"chinese traditional", "他們爲什麽不說中文", "ihqwctvzc91f659drss3x8bo0yb"},
+	{"czech", "Pročprostěnemluvíčesky", "Proprostnemluvesky-uyb24dma41a"},
+	{"hebrew", "למההםפשוטלאמדבריםעברית", "4dbcagdahymbxekheh6e0a7fei0b"},
+	{"hindi", "यहलोगहिन्दीक्योंनहींबोलसकतेहैं", "i1baa7eci9glrd9b2ae1bj0hfcgg6iyaf8o0a1dig0cd"},
+	{"japanese", "なぜみんな日本語を話してくれないのか", "n8jok5ay5dzabd5bym9f0cm5685rrjetr6pdxa"},
+	{"russian", "почемужеонинеговорятпорусски", "b1abfaaepdrnnbgefbadotcwatmq2g4l"},
+	{"spanish", "PorquénopuedensimplementehablarenEspañol", "PorqunopuedensimplementehablarenEspaol-fmd56a"},
+	{"vietnamese", "TạisaohọkhôngthểchỉnóitiếngViệt", "TisaohkhngthchnitingVit-kjcr8268qyxafd2f1b9g"},
+	{"3B", "3年B組金八先生", "3B-ww4c5e180e575a65lsy2b"},
+	{"super monkeys", "安室奈美恵-with-SUPER-MONKEYS", "-with-SUPER-MONKEYS-pc58ag80a8qai00g7n9n"},
+	{"another way", "Hello-Another-Way-それぞれの場所", "Hello-Another-Way--fc4qua05auwb3674vfr0b"},
+	{"roof", "ひとつ屋根の下2", "2-u9tlzr9756bt3uc0v"},
+	{"maji", "MajiでKoiする5秒前", "MajiKoi5-783gue6qz075azm5e"},
+	{"rumba", "パフィーdeルンバ", "de-jg4avhby1noc0d"},
+	{"speed", "そのスピードで", "d9juau41awczczp"},
+	{"ascii", "-> $1.00 <-", "-> $1.00 <--"},
+}
+
+func TestPunyEncode(t *testing.T) {
+	for _, v := range punycodeVectors {
+		got, err := punyEncode(v.input)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", v.name, err)
+			continue
+		}
+
+		if got != v.encoded {
+			t.Errorf("%s: got %q, want %q", v.name, got, v.encoded)
+		}
+	}
+}
+
+func TestToASCII(t *testing.T) {
+	tests := []struct {
+		domain string
+		want   string
+	}{
+		{"example.com", "example.com"},
+		{"Example.COM", "example.com"},
+		{"bücher.de", "xn--bcher-kva.de"},
+		{"mail.Bücher.de", "mail.xn--bcher-kva.de"},
+		{"例え.テスト", "xn--r8jz45g.xn--zckzah"},
+	}
+
+	for _, tt := range tests {
+		got, err := toASCII(tt.domain)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.domain, err)
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.domain, got, tt.want)
+		}
+	}
+
+	if _, err := toASCII("b\xffcher.de"); err == nil {
+		t.Error("invalid UTF-8 was accepted")
+	}
+}
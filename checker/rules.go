@@ -0,0 +1,258 @@
+package checker
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	r "github.com/rubikorg/rubik"
+)
+
+// RuleFactory creates an assertion from the parameter of a named rule,
+// i.e: for `min=3` the factory of `min` is called with "3". The
+// parameter is empty for rules used without one
+type RuleFactory func(param string) (r.Assertion, error)
+
+var namedRules = struct {
+	sync.RWMutex
+	m map[string]RuleFactory
+}{m: map[string]RuleFactory{
+	"required":     noParam("required", Required),
+	"present":      noParam("present", MustExist),
+	"bool":         noParam("bool", IsBool),
+	"forbidden":    noParam("forbidden", Forbidden),
+	"email":        noParam("email", IsEmail),
+	"alpha":        noParam("alpha", Alpha),
+	"alphanum":     noParam("alphanum", AlphaNumeric),
+	"ascii":        noParam("ascii", ASCIIOnly),
+	"printable":    noParam("printable", NoControlChars),
+	"positive":     noParam("positive", Positive),
+	"unique":       noParam("unique", SliceUnique),
+	"uuid":         noParam("uuid", IsUUID),
+	"url":          noParam("url", IsURL),
+	"ip":           noParam("ip", IsIP),
+	"ipv4":         noParam("ipv4", IsIPv4),
+	"ipv6":         noParam("ipv6", IsIPv6),
+	"cidr":         noParam("cidr", IsCIDR),
+	"hostname":     noParam("hostname", IsHostname),
+	"hexcolor":     noParam("hexcolor", IsHexColor),
+	"semver":       noParam("semver", IsSemver),
+	"e164":         noParam("e164", IsE164Phone),
+	"luhn":         noParam("luhn", IsLuhn),
+	"isbn":         noParam("isbn", IsISBN),
+	"iban":         noParam("iban", IsIBAN),
+	"base64":       noParam("base64", IsBase64),
+	"json":         noParam("json", IsJSON),
+	"date":         noParam("date", IsISO8601Date),
+	"min":          intParam("min", minRule),
+	"max":          intParam("max", maxRule),
+	"multipleof":   intParam("multipleof", MultipleOf),
+	"mingraphemes": intParam("mingraphemes", StrMinGraphemes),
+	"maxgraphemes": intParam("maxgraphemes", StrMaxGraphemes),
+	"maxbytes":     intParam("maxbytes", StrMaxBytes),
+	"oneof":        oneOfFactory,
+	"mime":         listParam("mime", FileMIME),
+	"ext":          listParam("ext", FileExt),
+	"maxsize":      intParam("maxsize", maxSizeRule),
+	"pattern":      patternFactory,
+}}
+
+// RegisterRule makes a named rule available to the `check` struct tag,
+// registering an existing name replaces its factory
+func RegisterRule(name string, factory RuleFactory) error {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.ContainsAny(name, ",= ") {
+		return fmt.Errorf("checker: invalid rule name %q", name)
+	}
+
+	if factory == nil {
+		return fmt.Errorf("checker: rule %s has no factory", name)
+	}
+
+	namedRules.Lock()
+	namedRules.m[name] = factory
+	namedRules.Unlock()
+	return nil
+}
+
+// ParseRules turns a comma separated list of named rules like
+// `required,min=3,oneof=a|b` into assertions, unknown rules and bad
+// parameters are reported as errors
+func ParseRules(spec string) ([]r.Assertion, error) {
+	var assertions []r.Assertion
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		a, err := parseRule(part)
+		if err != nil {
+			return nil, err
+		}
+		assertions = append(assertions, a)
+	}
+
+	return assertions, nil
+}
+
+func parseRule(rule string) (r.Assertion, error) {
+	name, param := rule, ""
+	if i := strings.Index(rule, "="); i >= 0 {
+		name, param = strings.TrimSpace(rule[:i]), strings.TrimSpace(rule[i+1:])
+	}
+
+	namedRules.RLock()
+	factory, ok := namedRules.m[name]
+	namedRules.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown rule %q", name)
+	}
+
+	a, err := factory(param)
+	if err != nil {
+		return nil, fmt.Errorf("rule %q: %v", name, err)
+	}
+
+	return a, nil
+}
+
+func noParam(name string, a r.Assertion) RuleFactory {
+	return func(param string) (r.Assertion, error) {
+		if param != "" {
+			return nil, fmt.Errorf("%s does not take a parameter", name)
+		}
+		return a, nil
+	}
+}
+
+func intParam(name string, factory func(int) r.Assertion) RuleFactory {
+	return func(param string) (r.Assertion, error) {
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			return nil, fmt.Errorf("%s needs an integer parameter but got %q", name, param)
+		}
+		return factory(n), nil
+	}
+}
+
+// listParam builds a rule from values separated by | like
+// `mime=image/png|image/jpeg`
+func listParam(name string, factory func(...string) r.Assertion) RuleFactory {
+	return func(param string) (r.Assertion, error) {
+		if param == "" {
+			return nil, fmt.Errorf("%s needs values separated by |", name)
+		}
+		return factory(strings.Split(param, "|")...), nil
+	}
+}
+
+func oneOfFactory(param string) (r.Assertion, error) {
+	if param == "" {
+		return nil, errors.New("oneof needs values separated by |")
+	}
+
+	values := strings.Split(param, "|")
+	strAssert := StrIsOneOf(values...)
+	return func(val interface{}) error {
+		if val == nil || describes(val, Rule{"enum", values}) {
+			return nil
+		}
+
+		if reflect.TypeOf(val).Kind() == reflect.String {
+			return strAssert(strValue(val))
+		}
+
+		// numbers and booleans are compared by their text form
+		s := fmt.Sprint(val)
+		for _, v := range values {
+			if v == s {
+				return nil
+			}
+		}
+
+		return errorf("str.oneof", Params{"values": values}, "$ must be one of %v", values)
+	}, nil
+}
+
+// patternFactory makes sure the pattern is registered when the rule is
+// parsed, so that a typo fails at startup instead of on every request
+func patternFactory(param string) (r.Assertion, error) {
+	if param == "" {
+		return nil, errors.New("pattern needs the name of a registered pattern")
+	}
+
+	namedPatterns.RLock()
+	_, ok := namedPatterns.m[param]
+	namedPatterns.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("pattern %s is not registered", param)
+	}
+
+	return StrPattern(param), nil
+}
+
+func maxSizeRule(n int) r.Assertion {
+	return FileMaxSize(int64(n))
+}
+
+// minRule checks the length of strings and lists and the value of
+// numbers
+func minRule(n int) r.Assertion {
+	strMin, sliceMin := StrMin(n), SliceMin(n)
+	return func(val interface{}) error {
+		if val == nil || describes(val, Rule{"min", n}) {
+			return nil
+		}
+
+		switch reflect.TypeOf(val).Kind() {
+		case reflect.String:
+			return strMin(strValue(val))
+		case reflect.Slice, reflect.Array:
+			return sliceMin(val)
+		}
+
+		f, err := AsFloat(val)
+		if err != nil {
+			return err
+		}
+
+		if f < float64(n) {
+			return errorf("num.min", Params{"min": n}, "$ must be at least %d", n)
+		}
+
+		return nil
+	}
+}
+
+// maxRule checks the length of strings and lists and the value of
+// numbers
+func maxRule(n int) r.Assertion {
+	strMax, sliceMax := StrMax(n), SliceMax(n)
+	return func(val interface{}) error {
+		if val == nil || describes(val, Rule{"max", n}) {
+			return nil
+		}
+
+		switch reflect.TypeOf(val).Kind() {
+		case reflect.String:
+			return strMax(strValue(val))
+		case reflect.Slice, reflect.Array:
+			return sliceMax(val)
+		}
+
+		f, err := AsFloat(val)
+		if err != nil {
+			return err
+		}
+
+		if f > float64(n) {
+			return errorf("num.max", Params{"max": n}, "$ must be at most %d", n)
+		}
+
+		return nil
+	}
+}
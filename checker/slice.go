@@ -0,0 +1,163 @@
+package checker
+
+import (
+	"fmt"
+	"reflect"
+
+	r "github.com/rubikorg/rubik"
+)
+
+// IsSlice checks if the value of the field is a slice or an array, it
+// works with []interface{} of decoded JSON as well as typed slices
+func IsSlice(val interface{}) error {
+	if val == nil {
+		return nil
+	}
+
+	kind := reflect.TypeOf(val).Kind()
+	if kind != reflect.Slice && kind != reflect.Array {
+		return errorf("slice.type", nil, "$ must be a list")
+	}
+
+	return nil
+}
+
+// SliceMin checks if the list has at least min elements
+func SliceMin(min int) r.Assertion {
+	return func(val interface{}) error {
+		if describes(val, Rule{"minItems", min}) {
+			return nil
+		}
+
+		err := IsSlice(val)
+		if err != nil || val == nil {
+			return err
+		}
+
+		if reflect.ValueOf(val).Len() < min {
+			return errorf("slice.min", Params{"min": min},
+				"$ must have at least %d elements", min)
+		}
+
+		return nil
+	}
+}
+
+// SliceMax checks if the list has at most max elements
+func SliceMax(max int) r.Assertion {
+	return func(val interface{}) error {
+		if describes(val, Rule{"maxItems", max}) {
+			return nil
+		}
+
+		err := IsSlice(val)
+		if err != nil || val == nil {
+			return err
+		}
+
+		if reflect.ValueOf(val).Len() > max {
+			return errorf("slice.max", Params{"max": max},
+				"$ must have at most %d elements", max)
+		}
+
+		return nil
+	}
+}
+
+// SliceUnique checks that no element of the list is repeated, the error
+// reports the index of the first duplicate. Numbers are compared by value
+// like Contains does, so 1 and 1.0 are duplicates
+func SliceUnique(val interface{}) error {
+	if describes(val, Rule{"uniqueItems", true}) {
+		return nil
+	}
+
+	err := IsSlice(val)
+	if err != nil || val == nil {
+		return err
+	}
+
+	elems := sliceElems(val)
+	for i := range elems {
+		for j := 0; j < i; j++ {
+			if elemEqual(elems[i], elems[j]) {
+				return errorf("slice.unique", Params{"index": i},
+					"$ must not have duplicate elements but element %d repeats", i)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Contains checks if the list has an element equal to the given value.
+// Numbers are compared by value so that 3 matches a decoded JSON 3.0
+func Contains(value interface{}) r.Assertion {
+	return func(val interface{}) error {
+		err := IsSlice(val)
+		if err != nil || val == nil {
+			return err
+		}
+
+		for _, elem := range sliceElems(val) {
+			if elemEqual(elem, value) {
+				return nil
+			}
+		}
+
+		return errorf("slice.contains", Params{"value": value},
+			"$ must contain %v", value)
+	}
+}
+
+// Each runs the given assertions over every element of the list, errors
+// are reported against indexed paths like `tags[2]`
+func Each(assertions ...r.Assertion) r.Assertion {
+	return func(val interface{}) error {
+		err := IsSlice(val)
+		if err != nil || val == nil {
+			return err
+		}
+
+		var errs ValidationErrors
+		for i, elem := range sliceElems(val) {
+			path := fmt.Sprintf("$[%d]", i)
+			for _, assert := range assertions {
+				if err := assert(elem); err != nil {
+					errs = append(errs, bindField(path, err)...)
+					break
+				}
+			}
+		}
+
+		if len(errs) == 0 {
+			return nil
+		}
+
+		return errs
+	}
+}
+
+// sliceElems returns the elements of a slice or an array, pointers are
+// dereferenced like the values of struct fields
+func sliceElems(val interface{}) []interface{} {
+	rv := reflect.ValueOf(val)
+	elems := make([]interface{}, rv.Len())
+	for i := range elems {
+		elems[i] = indirect(rv.Index(i))
+	}
+
+	return elems
+}
+
+func elemEqual(a, b interface{}) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+
+	af, aerr := AsFloat(a)
+	bf, berr := AsFloat(b)
+	_, aStr := a.(string)
+	_, bStr := b.(string)
+	return aerr == nil && berr == nil && !aStr && !bStr && af == bf
+}
@@ -0,0 +1,37 @@
+package checker
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSliceUnique(t *testing.T) {
+	tests := []struct {
+		name string
+		val  interface{}
+		code string
+	}{
+		{"nil", nil, ""},
+		{"unique", []int{1, 2, 3}, ""},
+		{"repeated", []string{"a", "b", "a"}, "slice.unique"},
+		{"number types", []interface{}{1, 1.0}, "slice.unique"},
+		{"json number", []interface{}{json.Number("2"), int64(2)}, "slice.unique"},
+		{"number and string", []interface{}{1, "1"}, ""},
+		{"maps", []interface{}{map[string]interface{}{"a": 1.0}, map[string]interface{}{"a": 1.0}},
+			"slice.unique"},
+		{"not a list", "abc", "slice.type"},
+	}
+
+	for _, tt := range tests {
+		expectCode(t, tt.name, SliceUnique(tt.val), tt.code)
+	}
+}
+
+func TestSliceUniqueAgreesWithContains(t *testing.T) {
+	var list []interface{}
+	json.Unmarshal([]byte(`[1, 2.0, 3]`), &list)
+	for _, v := range []interface{}{1, 2, int64(3)} {
+		expectCode(t, "contains", Contains(v)(list), "")
+		expectCode(t, "unique", SliceUnique(append(list, v)), "slice.unique")
+	}
+}
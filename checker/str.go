@@ -1,27 +1,20 @@
 package checker
 
 import (
-	"errors"
 	"fmt"
-	"reflect"
+	"unicode/utf8"
 
 	r "github.com/rubikorg/rubik"
 )
 
 // MustExist checks if value of the given field is nil or not
 func MustExist(val interface{}) error {
-	if val == nil {
-		return errors.New("$ is required")
+	if describes(val, Rule{"required", true}) {
+		return nil
 	}
 
-	return nil
-}
-
-// IsEmail checks if given field is an email or not
-func IsEmail(val interface{}) error {
-	err := IsStr(val)
-	if err != nil {
-		return err
+	if val == nil {
+		return errorf("required", nil, "$ is required")
 	}
 
 	return nil
@@ -35,45 +28,85 @@ func IsStr(val interface{}) error {
 		return nil
 	}
 
-	isReflectedAsString := reflect.TypeOf(val).Kind() == reflect.String
-	if isReflectedAsString {
-		return nil
-	}
+	_, err := AsString(val)
+	return err
+}
 
-	if _, ok := val.(string); !ok {
-		return errors.New("cannot be asserted as string")
-	}
+// StrMin checks if the value has at least minLen characters, characters
+// are counted as unicode code points so that "héllo" has 5 of them
+func StrMin(minLen int) r.Assertion {
+	return strMinLen(minLen, utf8.RuneCountInString)
+}
 
-	return nil
+// StrMax checks if the value has at most maxLen characters counted as
+// unicode code points
+func StrMax(maxLen int) r.Assertion {
+	return strMaxLen(maxLen, utf8.RuneCountInString)
 }
 
-func StrMin(minLen int) r.Assertion {
+// StrMinGraphemes is like StrMin but counts user-perceived characters so
+// that an emoji flag or a letter with combining accents counts once
+func StrMinGraphemes(minLen int) r.Assertion {
+	return strMinLen(minLen, graphemeCount)
+}
+
+// StrMaxGraphemes is like StrMax but counts user-perceived characters
+func StrMaxGraphemes(maxLen int) r.Assertion {
+	return strMaxLen(maxLen, graphemeCount)
+}
+
+// StrMaxBytes checks if the UTF-8 encoding of the value fits into
+// maxBytes, i.e: the size of a database column
+func StrMaxBytes(maxBytes int) r.Assertion {
 	return func(val interface{}) error {
 		err := IsStr(val)
-		if err != nil {
+		if err != nil || val == nil {
 			return err
 		}
 
-		if len(val.(string)) < minLen {
-			msg := fmt.Sprintf("minimum %d characters needed but value: $", minLen)
-			return errors.New(msg)
+		if len(strValue(val)) > maxBytes {
+			return errorf("str.max_bytes", Params{"max": maxBytes},
+				"$ must not be longer than %d bytes", maxBytes)
 		}
 
 		return nil
 	}
 }
 
-func StrMax(maxLen int) r.Assertion {
+func strMinLen(minLen int, count func(string) int) r.Assertion {
+	return func(val interface{}) error {
+		if describes(val, Rule{"minLength", minLen}) {
+			return nil
+		}
+
+		err := IsStr(val)
+		if err != nil || val == nil {
+			return err
+		}
+
+		if count(strValue(val)) < minLen {
+			return errorf("str.min", Params{"min": minLen},
+				"minimum %d characters needed but value: $", minLen)
+		}
+
+		return nil
+	}
+}
+
+func strMaxLen(maxLen int, count func(string) int) r.Assertion {
 	return func(val interface{}) error {
+		if describes(val, Rule{"maxLength", maxLen}) {
+			return nil
+		}
+
 		err := IsStr(val)
-		if err != nil {
+		if err != nil || val == nil {
 			return err
 		}
 
-		if len(val.(string)) > maxLen {
-			msg := fmt.Sprintf("maximum of %d characters allowed but value: $",
-				maxLen)
-			return errors.New(msg)
+		if count(strValue(val)) > maxLen {
+			return errorf("str.max", Params{"max": maxLen},
+				"maximum of %d characters allowed but value: $", maxLen)
 		}
 
 		return nil
@@ -83,67 +116,102 @@ func StrMax(maxLen int) r.Assertion {
 // StrAllow only allows the use of the runes given inside the
 // string value of the request field
 func StrAllow(runes ...rune) r.Assertion {
+	allowed := make(map[rune]bool, len(runes))
+	for _, ru := range runes {
+		allowed[ru] = true
+	}
+
+	what := fmt.Sprintf("the characters %q", string(runes))
+	params := Params{"allowed": string(runes)}
 	return func(val interface{}) error {
+		return checkRunes(val, "str.allow", params, what, func(ru rune) bool {
+			return allowed[ru]
+		})
+	}
+}
+
+// StrDeny rejects the string value of the request field if it contains
+// any of the given runes
+func StrDeny(runes ...rune) r.Assertion {
+	denied := make(map[rune]bool, len(runes))
+	for _, ru := range runes {
+		denied[ru] = true
+	}
+
+	return func(val interface{}) error {
+		err := IsStr(val)
+		if err != nil || val == nil {
+			return err
+		}
+
+		for pos, ru := range []rune(strValue(val)) {
+			if denied[ru] {
+				return errorf("str.deny", Params{"char": string(ru), "position": pos + 1},
+					"$ must not contain %q but found one at position %d", ru, pos+1)
+			}
+		}
+
 		return nil
 	}
 }
 
 // StrIsOneOf allowes only the values passed inside this method
-// as a viable value for the request field
+// as a viable value for the request field. The value is read through
+// AsString so that named string types and []byte are compared as well
 func StrIsOneOf(values ...string) r.Assertion {
 	return func(val interface{}) error {
-		if len(values) == 0 {
+		if len(values) == 0 || describes(val, Rule{"enum", values}) {
 			return nil
 		}
 
-		var vals []interface{}
-		for _, a := range values {
-			vals = append(vals, a)
+		if s, err := AsString(val); err == nil {
+			for _, v := range values {
+				if s == v {
+					return nil
+				}
+			}
 		}
 
-		ok := isOneOf(val, vals...)
-		if !ok {
-			return fmt.Errorf("$ must be one of %v", values)
-		}
+		return errorf("str.oneof", Params{"values": values},
+			"$ must be one of %v", values)
+	}
+}
 
+// IsBool checks if the value can be read as a boolean by AsBool
+func IsBool(val interface{}) error {
+	if val == nil || describes(val, Rule{"type", "boolean"}) {
 		return nil
 	}
+
+	_, err := AsBool(val)
+	return err
 }
 
+// StrBoolIsTrue checks if the value is true, booleans, 1 and the
+// spellings of TrueStrings are accepted
 func StrBoolIsTrue(val interface{}) error {
-	err := IsStr(val)
-	if err != nil {
-		return err
-	}
-	strVal, _ := val.(string)
-	switch strVal {
-	case "y", "yes", "true", "TRUE", "True":
-		return nil
-	default:
-		return errors.New("$ is not a truthy string")
+	b, err := AsBool(val)
+	if err != nil || !b {
+		return errorf("str.true", nil, "$ is not a truthy string")
 	}
+
+	return nil
 }
 
+// StrBoolIsFalse checks if the value is false, booleans, 0 and the
+// spellings of FalseStrings are accepted
 func StrBoolIsFalse(val interface{}) error {
-	err := IsStr(val)
-	if err != nil {
-		return err
-	}
-	strVal, _ := val.(string)
-	switch strVal {
-	case "n", "no", "false", "FALSE", "False":
-		return nil
-	default:
-		return errors.New("$ is not a falsy string")
+	b, err := AsBool(val)
+	if err != nil || b {
+		return errorf("str.false", nil, "$ is not a falsy string")
 	}
-}
 
-func isOneOf(t interface{}, values ...interface{}) bool {
-	for _, v := range values {
-		if t == v {
-			return true
-		}
-	}
+	return nil
+}
 
-	return false
+// strValue returns the string held by val through AsString. It must
+// only be called after IsStr succeeds
+func strValue(val interface{}) string {
+	s, _ := AsString(val)
+	return s
 }
@@ -0,0 +1,80 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGraphemeCount(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want int
+	}{
+		{"empty", "", 0},
+		{"ascii", "hello", 5},
+		{"precomposed accent", "héllo", 5},
+		{"combining accent", "he\u0301llo", 5},
+		{"stacked combining marks", "a\u0308\u0301\u0323", 1},
+		{"devanagari syllables", "हिन्दी", 2},
+		{"devanagari conjunct", "नमस्ते", 3},
+		{"devanagari ksha", "क्षि", 1},
+		{"devanagari sign", "नहीं", 2},
+		{"zwj family", "\U0001F468\u200d\U0001F469\u200d\U0001F467\u200d\U0001F466", 1},
+		{"two zwj families", "\U0001F468\u200d\U0001F469\u200d\U0001F467" +
+			"\U0001F469\u200d\U0001F469\u200d\U0001F466", 2},
+		{"flag", "\U0001F1E9\U0001F1EA", 1},
+		{"two flags", "\U0001F1E9\U0001F1EA\U0001F1EB\U0001F1F7", 2},
+		{"odd regional indicators", "\U0001F1E9\U0001F1EA\U0001F1EB", 2},
+		{"skin tone", "\U0001F44D\U0001F3FD", 1},
+		{"skin tone zwj", "\U0001F469\U0001F3FD\u200d\U0001F4BB", 1},
+		{"skin tones", "\U0001F44B\U0001F3FB\U0001F44B\U0001F3FF", 2},
+		{"variation selector", "❤\ufe0f", 1},
+		{"keycap", "1\ufe0f\u20e3", 1},
+		{"crlf", "a\r\nb", 3},
+		{"control", "a\u0301\n\u0301", 3},
+		{"hangul syllables", "한국어", 3},
+		{"hangul jamo", "\u1100\u1161\u11a8\u1100\u1161", 2},
+		{"mixed", "ok \U0001F44D\U0001F3FD!", 5},
+	}
+
+	for _, tt := range tests {
+		if got := graphemeCount(tt.s); got != tt.want {
+			t.Errorf("%s: graphemeCount(%+q) = %d, want %d", tt.name, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestStrLengths(t *testing.T) {
+	family := "\U0001F468\u200d\U0001F469\u200d\U0001F467\u200d\U0001F466"
+	cases := []struct {
+		name string
+		err  error
+		code string
+	}{
+		{"StrMin runes", StrMin(5)("héllo"), ""},
+		{"StrMin bytes are not runes", StrMin(6)("héllo"), "str.min"},
+		{"StrMax runes", StrMax(5)("héllo"), ""},
+		{"StrMax combining accent", StrMax(5)("he\u0301llo"), "str.max"},
+		{"StrMax devanagari runes", StrMax(5)("नमस्ते"), "str.max"},
+		{"StrMaxGraphemes combining accent", StrMaxGraphemes(5)("he\u0301llo"), ""},
+		{"StrMaxGraphemes devanagari", StrMaxGraphemes(3)("नमस्ते"), ""},
+		{"StrMaxGraphemes family", StrMaxGraphemes(1)(family), ""},
+		{"StrMaxGraphemes flags", StrMaxGraphemes(1)("\U0001F1E9\U0001F1EA\U0001F1EB\U0001F1F7"),
+			"str.max"},
+		{"StrMinGraphemes family", StrMinGraphemes(2)(family), "str.min"},
+		{"StrMinGraphemes skin tones", StrMinGraphemes(2)("\U0001F44B\U0001F3FB\U0001F44B\U0001F3FF"), ""},
+		{"StrMaxBytes ascii", StrMaxBytes(5)("hello"), ""},
+		{"StrMaxBytes accent", StrMaxBytes(5)("héllo"), "str.max_bytes"},
+		{"StrMaxBytes family", StrMaxBytes(25)(family), ""},
+		{"StrMaxBytes family over", StrMaxBytes(24)(family), "str.max_bytes"},
+		{"StrMaxBytes devanagari", StrMaxBytes(18)(strings.Repeat("न", 6)), ""},
+		{"StrMaxBytes devanagari over", StrMaxBytes(17)(strings.Repeat("न", 6)), "str.max_bytes"},
+		{"StrMaxBytes nil", StrMaxBytes(0)(nil), ""},
+		{"StrMaxBytes not a string", StrMaxBytes(5)(42), "str.type"},
+	}
+
+	for _, c := range cases {
+		expectCode(t, c.name, c.err, c.code)
+	}
+}
@@ -0,0 +1,126 @@
+package checker
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	r "github.com/rubikorg/rubik"
+)
+
+// TagName is the struct tag read by SchemaOf
+const TagName = "check"
+
+// tagSchemas caches the schema of every struct type that was parsed
+var tagSchemas sync.Map
+
+// SchemaOf builds the schema of an entity from the `check` tags of its
+// fields, i.e:
+//
+//	type SignupEntity struct {
+//		r.Entity
+//		Username string `json:"username" check:"required,min=3,max=20"`
+//		Email    string `json:"email" check:"required,email"`
+//	}
+//
+// Fields are named after their json name and fields of nested structs
+// are named by their dot-notation path like `address.zip`. A type is
+// only parsed once, the schema is cached for the next calls
+func SchemaOf(entity interface{}) (Schema, error) {
+	rt := reflect.TypeOf(entity)
+	for rt != nil && rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+
+	if rt == nil || rt.Kind() != reflect.Struct {
+		return Schema{}, fmt.Errorf("checker: SchemaOf needs a struct but got %v", rt)
+	}
+
+	if cached, ok := tagSchemas.Load(rt); ok {
+		return cached.(Schema), nil
+	}
+
+	fields := make(map[string][]r.Assertion)
+	if err := collectTags(rt, "", fields, map[reflect.Type]bool{}); err != nil {
+		return Schema{}, err
+	}
+
+	schema := Schema{Fields: fields}
+	tagSchemas.Store(rt, schema)
+	return schema, nil
+}
+
+// MustSchemaOf is like SchemaOf but panics if any of the tags cannot be
+// parsed, it is meant to be used while declaring routes so that a bad
+// tag stops the app from starting
+func MustSchemaOf(entity interface{}) Schema {
+	schema, err := SchemaOf(entity)
+	if err != nil {
+		panic(err)
+	}
+
+	return schema
+}
+
+// ValidateStruct validates the entity against the schema built from its
+// `check` tags
+func ValidateStruct(entity interface{}) error {
+	schema, err := SchemaOf(entity)
+	if err != nil {
+		return err
+	}
+
+	return schema.Validate(entity)
+}
+
+// collectTags parses the tags of rt into fields, seen guards against
+// recursive types while walking into nested structs
+func collectTags(rt reflect.Type, prefix string, fields map[string][]r.Assertion,
+	seen map[reflect.Type]bool) error {
+	seen[rt] = true
+	defer delete(seen, rt)
+
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		spec, hasTag := sf.Tag.Lookup(TagName)
+		if spec == "-" {
+			continue
+		}
+
+		st := sf.Type
+		if st.Kind() == reflect.Ptr {
+			st = st.Elem()
+		}
+		isNested := st.Kind() == reflect.Struct && !isLeafStruct(st) && !seen[st]
+
+		if sf.Anonymous && !hasTag {
+			if isNested {
+				if err := collectTags(st, prefix, fields, seen); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		name := prefix + fieldName(sf)
+		if hasTag {
+			assertions, err := ParseRules(spec)
+			if err != nil {
+				return fmt.Errorf("checker: %s.%s: %v", rt.Name(), sf.Name, err)
+			}
+			fields[name] = assertions
+		}
+
+		if isNested {
+			if err := collectTags(st, name+".", fields, seen); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package checker
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	r "github.com/rubikorg/rubik"
+)
+
+// TimeLayouts are the layouts tried in order when the time assertions
+// read a string value as a time
+var TimeLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// TimeLocation is the location of strings which do not carry their own
+// offset, like plain dates. It is also the calendar used by AgeAtLeast
+var TimeLocation = time.UTC
+
+// now is the clock of the relative bounds, tests replace it with a fixed
+// clock
+var now = time.Now
+
+// TimeLayout checks if the string value of the field can be read with
+// one of the given layouts, time.Time values always pass
+func TimeLayout(layouts ...string) r.Assertion {
+	return func(val interface{}) error {
+		if val == nil {
+			return nil
+		}
+
+		if _, ok := val.(time.Time); ok {
+			return nil
+		}
+
+		err := IsStr(val)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := parseTime(strValue(val), layouts); !ok {
+			return errorf("time.layout", Params{"layouts": layouts},
+				"$ must be a time like %s", strings.Join(layouts, " or "))
+		}
+
+		return nil
+	}
+}
+
+// TimeFormat reads string values with its own layouts instead of
+// TimeLayouts, so that a field checked with TimeLayout can be bounded as
+// well, i.e: TimeFormat{"02/01/2006"}.AgeAtLeast(18). A nil TimeFormat
+// uses TimeLayouts, like the functions of the same names do
+type TimeFormat []string
+
+// Before checks if the time value of the field is before t
+func Before(t time.Time) r.Assertion {
+	return TimeFormat(nil).Before(t)
+}
+
+// Before checks if the time value of the field is before t
+func (tf TimeFormat) Before(t time.Time) r.Assertion {
+	return func(val interface{}) error {
+		return tf.check(val, func(v time.Time) error {
+			if !v.Before(t) {
+				return errorf("time.before", Params{"time": t.Format(time.RFC3339)},
+					"$ must be before %s", t.Format(time.RFC3339))
+			}
+			return nil
+		})
+	}
+}
+
+// After checks if the time value of the field is after t
+func After(t time.Time) r.Assertion {
+	return TimeFormat(nil).After(t)
+}
+
+// After checks if the time value of the field is after t
+func (tf TimeFormat) After(t time.Time) r.Assertion {
+	return func(val interface{}) error {
+		return tf.check(val, func(v time.Time) error {
+			if !v.After(t) {
+				return errorf("time.after", Params{"time": t.Format(time.RFC3339)},
+					"$ must be after %s", t.Format(time.RFC3339))
+			}
+			return nil
+		})
+	}
+}
+
+// NotInFuture checks if the time value of the field is not later than
+// the current time
+func NotInFuture(val interface{}) error {
+	return TimeFormat(nil).NotInFuture(val)
+}
+
+// NotInFuture checks if the time value of the field is not later than
+// the current time
+func (tf TimeFormat) NotInFuture(val interface{}) error {
+	return tf.check(val, func(v time.Time) error {
+		if v.After(now()) {
+			return errorf("time.not_in_future", nil, "$ must not be in the future")
+		}
+		return nil
+	})
+}
+
+// WithinLast checks if the time value of the field lies inside the last
+// d duration, i.e: WithinLast(24 * time.Hour)
+func WithinLast(d time.Duration) r.Assertion {
+	return TimeFormat(nil).WithinLast(d)
+}
+
+// WithinLast checks if the time value of the field lies inside the last
+// d duration
+func (tf TimeFormat) WithinLast(d time.Duration) r.Assertion {
+	return func(val interface{}) error {
+		return tf.check(val, func(v time.Time) error {
+			current := now()
+			if v.After(current) || v.Before(current.Add(-d)) {
+				return errorf("time.within_last", Params{"duration": d.String()},
+					"$ must be within the last %s", d)
+			}
+			return nil
+		})
+	}
+}
+
+// AgeAtLeast treats the value of the field as a birth date and checks if
+// the age is at least the given years, i.e: AgeAtLeast(18)
+func AgeAtLeast(years int) r.Assertion {
+	return TimeFormat(nil).AgeAtLeast(years)
+}
+
+// AgeAtLeast treats the value of the field as a birth date and checks if
+// the age is at least the given years
+func (tf TimeFormat) AgeAtLeast(years int) r.Assertion {
+	return func(val interface{}) error {
+		return tf.check(val, func(born time.Time) error {
+			born = born.In(TimeLocation)
+			today := now().In(TimeLocation)
+			if born.AddDate(years, 0, 0).After(today) {
+				return errorf("time.age", Params{"years": years},
+					"$ must be at least %d years ago", years)
+			}
+			return nil
+		})
+	}
+}
+
+func (tf TimeFormat) check(val interface{}, check func(time.Time) error) error {
+	if val == nil {
+		return nil
+	}
+
+	t, err := tf.toTime(val)
+	if err != nil {
+		return err
+	}
+
+	return check(t)
+}
+
+// layouts are the layouts of the format, TimeLayouts is read when the
+// value is checked so that changes to it still apply
+func (tf TimeFormat) layouts() []string {
+	if tf == nil {
+		return TimeLayouts
+	}
+
+	return tf
+}
+
+// toTime reads time.Time values and strings in one of the layouts of the
+// format as a time
+func (tf TimeFormat) toTime(val interface{}) (time.Time, error) {
+	switch v := val.(type) {
+	case time.Time:
+		return v, nil
+	case *time.Time:
+		if v != nil {
+			return *v, nil
+		}
+	}
+
+	if val != nil && reflect.TypeOf(val).Kind() == reflect.String {
+		if t, ok := parseTime(strValue(val), tf.layouts()); ok {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, errorf("time.type", nil, "$ is not a valid time")
+}
+
+func parseTime(s string, layouts []string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range layouts {
+		t, err := time.ParseInLocation(layout, s, TimeLocation)
+		if err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,110 @@
+package checker
+
+import (
+	"testing"
+	"time"
+)
+
+// fixClock makes now return at until restore is called
+func fixClock(at time.Time) (restore func()) {
+	prev := now
+	now = func() time.Time { return at }
+	return func() { now = prev }
+}
+
+func TestNotInFuture(t *testing.T) {
+	defer fixClock(time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC))()
+	tests := []struct {
+		val  interface{}
+		code string
+	}{
+		{nil, ""},
+		{"2024-05-10T12:00:00Z", ""},
+		{"2024-05-10T11:59:59Z", ""},
+		{"2024-05-10T12:00:01Z", "time.not_in_future"},
+		{"2024-05-10T13:00:00+02:00", ""},
+		{"2024-05-11", "time.not_in_future"},
+		{time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), "time.not_in_future"},
+		{"yesterday", "time.type"},
+	}
+
+	for _, tt := range tests {
+		expectCode(t, "NotInFuture", NotInFuture(tt.val), tt.code)
+	}
+}
+
+func TestWithinLast(t *testing.T) {
+	defer fixClock(time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC))()
+	check := WithinLast(24 * time.Hour)
+	tests := []struct {
+		val  string
+		code string
+	}{
+		{"2024-05-10T12:00:00Z", ""},
+		{"2024-05-09T12:00:00Z", ""},
+		{"2024-05-09T11:59:59Z", "time.within_last"},
+		{"2024-05-10T12:00:01Z", "time.within_last"},
+	}
+
+	for _, tt := range tests {
+		expectCode(t, "WithinLast "+tt.val, check(tt.val), tt.code)
+	}
+}
+
+func TestAgeAtLeast(t *testing.T) {
+	tests := []struct {
+		name  string
+		today time.Time
+		born  string
+		code  string
+	}{
+		{"birthday", time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC), "2006-06-15", ""},
+		{"day before birthday", time.Date(2024, 6, 14, 23, 59, 0, 0, time.UTC),
+			"2006-06-15", "time.age"},
+		{"older", time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC), "1990-01-01", ""},
+		{"leap day on feb 28", time.Date(2022, 2, 28, 12, 0, 0, 0, time.UTC),
+			"2004-02-29", "time.age"},
+		{"leap day on mar 1", time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC), "2004-02-29", ""},
+		{"leap day in leap year", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+			"2006-02-28", ""},
+		{"calendar of TimeLocation", time.Date(2024, 6, 14, 23, 0, 0, 0,
+			time.FixedZone("", -2*3600)), "2006-06-15", ""},
+	}
+
+	check := AgeAtLeast(18)
+	for _, tt := range tests {
+		restore := fixClock(tt.today)
+		expectCode(t, tt.name, check(tt.born), tt.code)
+		restore()
+	}
+}
+
+func TestTimeFormat(t *testing.T) {
+	defer fixClock(time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC))()
+	european := TimeFormat{"02/01/2006"}
+	signup := All(TimeLayout("02/01/2006"), european.AgeAtLeast(18))
+
+	tests := []struct {
+		name string
+		err  error
+		code string
+	}{
+		{"age", signup("10/05/2006"), ""},
+		{"age too young", signup("11/05/2006"), "time.age"},
+		{"age global layouts", AgeAtLeast(18)("10/05/2006"), "time.type"},
+		{"age wrong layout", european.AgeAtLeast(18)("2006-05-10"), "time.type"},
+		{"before", european.Before(now())("09/05/2024"), ""},
+		{"after", european.After(now())("09/05/2024"), "time.after"},
+		{"not in future", european.NotInFuture("11/05/2024"), "time.not_in_future"},
+		{"within last", european.WithinLast(48 * time.Hour)("09/05/2024"), ""},
+		{"time value", european.NotInFuture(now()), ""},
+		{"field after", european.FieldAfter("end", "start")(map[string]interface{}{
+			"start": "09/05/2024", "end": "08/05/2024"}), "fields.after"},
+		{"field after global layouts", FieldAfter("end", "start")(map[string]interface{}{
+			"start": "09/05/2024", "end": "10/05/2024"}), "time.type"},
+	}
+
+	for _, tt := range tests {
+		expectCode(t, tt.name, tt.err, tt.code)
+	}
+}
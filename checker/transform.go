@@ -0,0 +1,311 @@
+package checker
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Transformer rewrites the value of a field before the assertions of a
+// Schema run, i.e: trimming spaces. Returning an error stops the
+// transformation of that field and is reported like an assertion error
+type Transformer func(val interface{}) (interface{}, error)
+
+// Normalizer is a unicode normalization form, the forms of
+// golang.org/x/text/unicode/norm like norm.NFC satisfy it
+type Normalizer interface {
+	String(s string) string
+}
+
+// Trim removes leading and trailing white space
+func Trim(val interface{}) (interface{}, error) {
+	return mapStr(val, strings.TrimSpace), nil
+}
+
+// Lower converts the value to lower case
+func Lower(val interface{}) (interface{}, error) {
+	return mapStr(val, strings.ToLower), nil
+}
+
+// Upper converts the value to upper case
+func Upper(val interface{}) (interface{}, error) {
+	return mapStr(val, strings.ToUpper), nil
+}
+
+// CollapseSpaces trims the value and replaces every run of white space
+// inside it with a single space
+func CollapseSpaces(val interface{}) (interface{}, error) {
+	return mapStr(val, func(s string) string {
+		return strings.Join(strings.Fields(s), " ")
+	}), nil
+}
+
+// NormalizeUnicode brings the value into the given normalization form so
+// that visually equal strings compare equal, i.e: NormalizeUnicode(norm.NFC)
+func NormalizeUnicode(form Normalizer) Transformer {
+	return func(val interface{}) (interface{}, error) {
+		return mapStr(val, form.String), nil
+	}
+}
+
+// StripTags removes HTML tags from the value and keeps the text between
+// them
+func StripTags(val interface{}) (interface{}, error) {
+	return mapStr(val, stripTags), nil
+}
+
+// ToInt converts numeric strings, floats without a fraction and
+// json.Number into an int
+func ToInt(val interface{}) (interface{}, error) {
+	if val == nil {
+		return nil, nil
+	}
+
+	i, err := AsInt(val)
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(int(i)) != i {
+		return nil, errOverflow
+	}
+
+	return int(i), nil
+}
+
+// normalize runs the transformers of the schema. The new values are
+// written back into the entity when it is a map or a pointer to a struct
+// whose field can hold them, they are returned as well so that the
+// assertions see them even when the entity could not be changed. Numbers
+// which the field cannot hold exactly are reported instead of written
+func (s Schema) normalize(entity interface{}) (map[string]interface{}, ValidationErrors) {
+	if len(s.Transforms) == 0 {
+		return nil, nil
+	}
+
+	fields := make([]string, 0, len(s.Transforms))
+	for field := range s.Transforms {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	values := make(map[string]interface{})
+	var errs ValidationErrors
+	for _, field := range fields {
+		val, found := lookupField(entity, field)
+		if !found {
+			continue
+		}
+
+		var err error
+		for _, transform := range s.Transforms[field] {
+			val, err = transform(val)
+			if err != nil {
+				break
+			}
+		}
+
+		if err != nil {
+			errs = append(errs, bindField(field, err)...)
+			continue
+		}
+
+		if err := setField(entity, field, val); err != nil {
+			errs = append(errs, bindField(field, err)...)
+			continue
+		}
+		values[field] = val
+	}
+
+	return values, errs
+}
+
+// Normalize only runs the transformers of the schema on the entity
+// without validating it
+func (s Schema) Normalize(entity interface{}) error {
+	_, errs := s.normalize(entity)
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
+
+func mapStr(val interface{}, fn func(string) string) interface{} {
+	if val == nil || reflect.TypeOf(val).Kind() != reflect.String {
+		return val
+	}
+
+	return fn(strValue(val))
+}
+
+func stripTags(s string) string {
+	var b strings.Builder
+	inTag := false
+	var quote rune
+	for _, c := range s {
+		switch {
+		case inTag && quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case inTag && (c == '"' || c == '\''):
+			quote = c
+		case inTag && c == '>':
+			inTag = false
+		case inTag:
+		case c == '<':
+			inTag = true
+		default:
+			b.WriteRune(c)
+		}
+	}
+
+	return b.String()
+}
+
+// setField writes val into the field of the entity found by name or
+// dot-notation path. Fields which cannot hold a value of its type are
+// left alone, numbers which overflow the field or lose their fraction
+// are reported as errors
+func setField(entity interface{}, path string, val interface{}) error {
+	return setValue(reflect.ValueOf(entity), strings.Split(path, "."), val)
+}
+
+func setValue(rv reflect.Value, parts []string, val interface{}) error {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+
+		key := reflect.ValueOf(parts[0]).Convert(rv.Type().Key())
+		if len(parts) > 1 {
+			child := rv.MapIndex(key)
+			if !child.IsValid() {
+				return nil
+			}
+			return setValue(child, parts[1:], val)
+		}
+
+		v, ok, err := fitValue(val, rv.Type().Elem())
+		if ok {
+			rv.SetMapIndex(key, v)
+		}
+		return err
+	case reflect.Struct:
+		f, ok := structField(rv, parts[0])
+		if !ok {
+			return nil
+		}
+
+		if len(parts) > 1 {
+			return setValue(f, parts[1:], val)
+		}
+
+		v, ok, err := fitValue(val, f.Type())
+		if ok && f.CanSet() {
+			f.Set(v)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// fitValue converts val into a value of type t when it is safe to do so,
+// numbers are converted between numeric kinds and pointers are created
+// for pointer fields. The error reports numbers which t cannot hold
+func fitValue(val interface{}, t reflect.Type) (reflect.Value, bool, error) {
+	if val == nil {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+			return reflect.Zero(t), true, nil
+		}
+		return reflect.Value{}, false, nil
+	}
+
+	v := reflect.ValueOf(val)
+	if v.Type().AssignableTo(t) {
+		return v, true, nil
+	}
+
+	if t.Kind() == reflect.Ptr {
+		inner, ok, err := fitValue(val, t.Elem())
+		if !ok {
+			return reflect.Value{}, false, err
+		}
+		p := reflect.New(t.Elem())
+		p.Elem().Set(inner)
+		return p, true, nil
+	}
+
+	if isNumberKind(v.Kind()) && isNumberKind(t.Kind()) {
+		n, err := convertNumber(v, t)
+		return n, err == nil, err
+	}
+
+	if v.Kind() == reflect.String && t.Kind() == reflect.String {
+		return v.Convert(t), true, nil
+	}
+
+	return reflect.Value{}, false, nil
+}
+
+// convertNumber converts v into the numeric type t, values which would
+// wrap around or be truncated by the conversion are reported instead
+func convertNumber(v reflect.Value, t reflect.Type) (reflect.Value, error) {
+	n := reflect.New(t).Elem()
+	overflow := errorf("num.overflow", Params{"type": t.Kind().String()},
+		"$ does not fit into %s", t.Kind())
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := AsInt(v.Interface())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if n.OverflowInt(i) {
+			return reflect.Value{}, overflow
+		}
+		n.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		var u uint64
+		if k := v.Kind(); k >= reflect.Uint && k <= reflect.Uintptr {
+			u = v.Uint()
+		} else {
+			i, err := AsInt(v.Interface())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			if i < 0 {
+				return reflect.Value{}, overflow
+			}
+			u = uint64(i)
+		}
+		if n.OverflowUint(u) {
+			return reflect.Value{}, overflow
+		}
+		n.SetUint(u)
+	default:
+		f := v.Convert(reflect.TypeOf(float64(0))).Float()
+		if n.OverflowFloat(f) {
+			return reflect.Value{}, overflow
+		}
+		n.SetFloat(f)
+	}
+
+	return n, nil
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Float64
+}
@@ -0,0 +1,160 @@
+package checker
+
+import (
+	"reflect"
+	"testing"
+
+	r "github.com/rubikorg/rubik"
+)
+
+type profile struct {
+	Name  string   `json:"name"`
+	Level int8     `json:"level"`
+	Count int      `json:"count"`
+	Score uint16   `json:"score"`
+	Ratio float32  `json:"ratio"`
+	Nick  *string  `json:"nick"`
+	Home  *address `json:"home"`
+}
+
+// returns makes a transformer which replaces the value with v
+func returns(v interface{}) Transformer {
+	return func(interface{}) (interface{}, error) {
+		return v, nil
+	}
+}
+
+func TestNormalizeStruct(t *testing.T) {
+	p := &profile{Name: "  Ann   Lee ", Nick: nil, Home: &address{City: " rome "}}
+	schema := Schema{Transforms: map[string][]Transformer{
+		"name":      {CollapseSpaces},
+		"level":     {returns(int64(100))},
+		"count":     {returns(3.0)},
+		"score":     {returns(int64(65535))},
+		"ratio":     {returns(0.5)},
+		"nick":      {returns("annie")},
+		"home.city": {Trim, Upper},
+	}}
+
+	if err := schema.Normalize(p); err != nil {
+		t.Fatal(err)
+	}
+
+	nick := "annie"
+	want := &profile{Name: "Ann Lee", Level: 100, Count: 3, Score: 65535, Ratio: 0.5,
+		Nick: &nick, Home: &address{City: "ROME"}}
+	if !reflect.DeepEqual(p, want) {
+		t.Errorf("got %+v, want %+v", p, want)
+	}
+
+	byValue := profile{Name: " Ann "}
+	schema = Schema{
+		Fields:     map[string][]r.Assertion{"name": {StrIsOneOf("Ann")}},
+		Transforms: map[string][]Transformer{"name": {Trim}},
+	}
+	if err := schema.Validate(byValue); err != nil {
+		t.Errorf("the normalized value was not checked: %v", err)
+	}
+	if byValue.Name != " Ann " {
+		t.Errorf("a struct passed by value was changed: %q", byValue.Name)
+	}
+}
+
+func TestNormalizeNumbers(t *testing.T) {
+	tests := []struct {
+		name  string
+		field string
+		val   interface{}
+		code  string
+	}{
+		{"int8 overflow", "level", 300, "num.overflow"},
+		{"int8 underflow", "level", -129, "num.overflow"},
+		{"int8 max", "level", 127, ""},
+		{"fraction into int", "count", 3.9, "num.integer"},
+		{"integral float into int", "count", 4.0, ""},
+		{"negative into uint", "score", -1, "num.overflow"},
+		{"uint16 overflow", "score", uint64(65536), "num.overflow"},
+		{"float32 overflow", "ratio", 1e39, "num.overflow"},
+	}
+
+	for _, tt := range tests {
+		p := &profile{Level: 7, Count: 7, Score: 7, Ratio: 7}
+		schema := Schema{
+			Fields:     map[string][]r.Assertion{tt.field: {Positive}},
+			Transforms: map[string][]Transformer{tt.field: {returns(tt.val)}},
+		}
+
+		err := schema.Validate(p)
+		expectCode(t, tt.name, err, tt.code)
+		if tt.code == "" {
+			continue
+		}
+
+		if errs := err.(ValidationErrors); len(errs) != 1 || errs[0].Field != tt.field {
+			t.Errorf("%s: got %v, want a single error for %s", tt.name, errs, tt.field)
+		}
+
+		if *p != (profile{Level: 7, Count: 7, Score: 7, Ratio: 7}) {
+			t.Errorf("%s: the field was written: %+v", tt.name, *p)
+		}
+	}
+}
+
+func TestNormalizeMap(t *testing.T) {
+	m := map[string]interface{}{
+		"name":    " Ann ",
+		"age":     "42",
+		"address": map[string]interface{}{"city": " rome "},
+	}
+	schema := Schema{Transforms: map[string][]Transformer{
+		"name":         {Trim, Lower},
+		"age":          {ToInt},
+		"address.city": {Trim},
+		"missing":      {Trim},
+	}}
+
+	if err := schema.Normalize(m); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{
+		"name":    "ann",
+		"age":     42,
+		"address": map[string]interface{}{"city": "rome"},
+	}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("got %v, want %v", m, want)
+	}
+
+	small := map[string]int8{"level": 1}
+	schema = Schema{Transforms: map[string][]Transformer{"level": {returns(300)}}}
+	expectCode(t, "typed map overflow", schema.Normalize(small), "num.overflow")
+	if small["level"] != 1 {
+		t.Errorf("typed map overflow: got %d, want 1", small["level"])
+	}
+
+	expectCode(t, "to int", Schema{Transforms: map[string][]Transformer{
+		"age": {ToInt}}}.Normalize(map[string]interface{}{"age": "4.5"}), "num.integer")
+}
+
+func TestTransformers(t *testing.T) {
+	tests := []struct {
+		name      string
+		transform Transformer
+		val       interface{}
+		want      interface{}
+	}{
+		{"trim", Trim, "\t a b \n", "a b"},
+		{"trim keeps numbers", Trim, 3, 3},
+		{"collapse", CollapseSpaces, " a \t\n b  c ", "a b c"},
+		{"upper", Upper, "été", "ÉTÉ"},
+		{"strip tags", StripTags, `<a title="x>y">link</a> & <b>bold</b>`, "link & bold"},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.transform(tt.val)
+		if err != nil || got != tt.want {
+			t.Errorf("%s: got %v (%v), want %v", tt.name, got, err, tt.want)
+		}
+	}
+}
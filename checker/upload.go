@@ -0,0 +1,233 @@
+package checker
+
+import (
+	"bytes"
+	"image"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"sort"
+
+	r "github.com/rubikorg/rubik"
+)
+
+var errInvalidForm = errorf("body.multipart", nil, "the body is not a valid form")
+
+// UploadRule bounds the files sent under one field of a multipart form.
+// MaxSize is in bytes and MIME holds the allowed media types like
+// FileMIME does, the image header is decoded when MinImage or MaxImage
+// bound a side. Zero values do not check anything
+type UploadRule struct {
+	MaxSize  int64
+	MIME     []string
+	MinImage ImageSize
+	MaxImage ImageSize
+}
+
+// Uploads checks the files of a multipart request while the body is read
+// and before the form parser stores any part on disk. The body is bound
+// to maxBody bytes, zero leaves it unbounded, and every file part of a
+// field of rules is streamed through its checks, an oversized part stops
+// the read as soon as it passes its limit. Accepted bodies are kept in
+// memory and handed back to the request so that the form can be parsed
+// as usual. Use it as the first middleware of the upload route, i.e:
+//
+//	Middlewares: []r.Controller{checker.Uploads(10<<20, map[string]checker.UploadRule{
+//		"avatar": {MaxSize: 2 << 20, MIME: []string{"image/*"}},
+//	})}
+//
+// A form which was already parsed is checked through its file headers
+func Uploads(maxBody int64, rules map[string]UploadRule) r.Controller {
+	return func(req *r.Request) {
+		err := CheckUploads(req.Raw, maxBody, rules)
+		if err == nil {
+			return
+		}
+
+		status := http.StatusBadRequest
+		if errs, ok := err.(ValidationErrors); ok && errs[0].Code == "body.max_size" {
+			status = http.StatusRequestEntityTooLarge
+		}
+		req.Throw(status, Localize(err, LocaleFromRequest(req.Raw)), r.Type.JSON)
+	}
+}
+
+// CheckUploads is the check of Uploads for plain net/http handlers,
+// requests which are not multipart forms pass. The errors are of type
+// ValidationErrors, the `body.max_size` code tells that the whole body
+// passed maxBody
+func CheckUploads(req *http.Request, maxBody int64, rules map[string]UploadRule) error {
+	if req.MultipartForm != nil {
+		return checkParsedUploads(req.MultipartForm, rules)
+	}
+
+	// req.MultipartReader is not used as it keeps the form parser from
+	// reading the body again
+	mediaType, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/form-data" {
+		return nil
+	}
+	if params["boundary"] == "" {
+		return ValidationErrors{errInvalidForm}
+	}
+
+	body := req.Body
+	if maxBody > 0 {
+		body = http.MaxBytesReader(nil, body, maxBody)
+	}
+
+	// the read bytes are kept to give the body back to the form parser
+	var read bytes.Buffer
+	counted := &countingReader{r: io.TeeReader(body, &read)}
+	mr := multipart.NewReader(counted, params["boundary"])
+
+	var errs ValidationErrors
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return bodyError(counted, maxBody)
+		}
+
+		var partErrs ValidationErrors
+		rule, ok := rules[p.FormName()]
+		if !ok || p.FileName() == "" {
+			_, err = io.Copy(ioutil.Discard, p)
+		} else {
+			partErrs, err = checkPart(p, rule)
+		}
+		if err != nil {
+			return bodyError(counted, maxBody)
+		}
+
+		errs = append(errs, partErrs...)
+		// the rest of the body is not read after an oversized part
+		if len(partErrs) > 0 && partErrs[0].Code == "file.max_size" {
+			return errs
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	req.Body = ioutil.NopCloser(io.MultiReader(&read, body))
+	return nil
+}
+
+// checkPart streams a file part through the checks of rule, the part is
+// read up to one byte past its size limit. The error is only set when
+// the body could not be read
+func checkPart(p *multipart.Part, rule UploadRule) (ValidationErrors, error) {
+	var src io.Reader = p
+	if rule.MaxSize > 0 {
+		src = io.LimitReader(p, rule.MaxSize+1)
+	}
+	counted := &countingReader{r: src}
+
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(counted, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	head = head[:n]
+
+	var ve error
+	if len(rule.MIME) > 0 {
+		ve = checkMIME(http.DetectContentType(head), rule.MIME)
+	}
+
+	if ve == nil && rule.bindsImage() {
+		cfg, _, err := image.DecodeConfig(io.MultiReader(bytes.NewReader(head), counted))
+		if err != nil {
+			ve = errorf("file.image", nil, "$ must be a PNG, JPEG or GIF image")
+		} else {
+			ve = checkDimensions(cfg.Width, cfg.Height, rule.MinImage, rule.MaxImage)
+		}
+	}
+
+	if _, err := io.Copy(ioutil.Discard, counted); err != nil {
+		return nil, err
+	}
+
+	if rule.MaxSize > 0 && counted.n > rule.MaxSize {
+		ve = maxSizeError(rule.MaxSize, counted.n)
+	}
+
+	return bindField(p.FormName(), ve), nil
+}
+
+// checkParsedUploads runs the file assertions matching the rules on the
+// file headers of a parsed form
+func checkParsedUploads(form *multipart.Form, rules map[string]UploadRule) error {
+	fields := make([]string, 0, len(rules))
+	for field := range rules {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var errs ValidationErrors
+	for _, field := range fields {
+		assertions := rules[field].assertions()
+		for _, fh := range form.File[field] {
+			for _, assert := range assertions {
+				if err := assert(fh); err != nil {
+					errs = append(errs, bindField(field, err)...)
+					break
+				}
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
+
+func (rule UploadRule) assertions() []r.Assertion {
+	var assertions []r.Assertion
+	if rule.MaxSize > 0 {
+		assertions = append(assertions, FileMaxSize(rule.MaxSize))
+	}
+	if len(rule.MIME) > 0 {
+		assertions = append(assertions, FileMIME(rule.MIME...))
+	}
+	if rule.bindsImage() {
+		assertions = append(assertions, ImageDimensions(rule.MinImage, rule.MaxImage))
+	}
+
+	return assertions
+}
+
+func (rule UploadRule) bindsImage() bool {
+	return rule.MinImage != (ImageSize{}) || rule.MaxImage != (ImageSize{})
+}
+
+// bodyError tells a body which failed to read because it passed maxBody
+// from a broken form
+func bodyError(counted *countingReader, maxBody int64) ValidationErrors {
+	if maxBody > 0 && counted.n >= maxBody {
+		return ValidationErrors{errorf("body.max_size", Params{"max": maxBody},
+			"the body must not be larger than %d bytes", maxBody)}
+	}
+
+	return ValidationErrors{errInvalidForm}
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
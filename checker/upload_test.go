@@ -0,0 +1,136 @@
+package checker
+
+import (
+	"bytes"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type formPart struct {
+	field, file string
+	content     []byte
+}
+
+// uploadRequest builds a multipart request of the parts, the returned
+// reader counts how much of the body was read
+func uploadRequest(t *testing.T, parts ...formPart) (*http.Request, *countingReader) {
+	t.Helper()
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for _, p := range parts {
+		var err error
+		if p.file == "" {
+			err = w.WriteField(p.field, string(p.content))
+		} else {
+			var pw interface{ Write([]byte) (int, error) }
+			pw, err = w.CreateFormFile(p.field, p.file)
+			if err == nil {
+				_, err = pw.Write(p.content)
+			}
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	w.Close()
+
+	counted := &countingReader{r: &body}
+	req := httptest.NewRequest("POST", "/upload", ioutil.NopCloser(counted))
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req, counted
+}
+
+func TestCheckUploads(t *testing.T) {
+	photo := pngImage(t, 200, 100)
+	large := bytes.Repeat([]byte{0}, 1<<20)
+	rules := map[string]UploadRule{
+		"avatar": {MaxSize: 4 << 10, MIME: []string{"image/*"}, MaxImage: ImageSize{400, 400}},
+		"thumb":  {MaxImage: ImageSize{100, 100}},
+	}
+
+	tests := []struct {
+		name    string
+		maxBody int64
+		parts   []formPart
+		field   string
+		code    string
+	}{
+		{"accepted", 1 << 20, []formPart{{"title", "", []byte("me")}, {"avatar", "me.png", photo}},
+			"", ""},
+		{"oversized part", 0, []formPart{{"avatar", "me.png", append(photo, large...)}},
+			"avatar", "file.max_size"},
+		{"sniffed type", 0, []formPart{{"avatar", "me.png", []byte("plain text")}},
+			"avatar", "file.mime"},
+		{"dimensions", 0, []formPart{{"thumb", "me.png", photo}}, "thumb", "file.dimensions"},
+		{"not an image", 0, []formPart{{"thumb", "me.png", []byte("GIF89a")}}, "thumb", "file.image"},
+		{"body limit", 64 << 10, []formPart{{"other", "big.bin", large}}, "", "body.max_size"},
+		{"fields without rules", 2 << 20, []formPart{{"other", "big.bin", large}}, "", ""},
+	}
+
+	for _, tt := range tests {
+		req, counted := uploadRequest(t, tt.parts...)
+		err := CheckUploads(req, tt.maxBody, rules)
+		expectCode(t, tt.name, err, tt.code)
+		if err != nil && err.(ValidationErrors)[0].Field != tt.field {
+			t.Errorf("%s: got field %q, want %q", tt.name, err.(ValidationErrors)[0].Field, tt.field)
+		}
+
+		if tt.code == "file.max_size" || tt.code == "body.max_size" {
+			if counted.n >= 256<<10 {
+				t.Errorf("%s: %d bytes of the body were read", tt.name, counted.n)
+			}
+		}
+	}
+}
+
+func TestCheckUploadsKeepsBody(t *testing.T) {
+	photo := pngImage(t, 200, 100)
+	req, _ := uploadRequest(t, formPart{"title", "", []byte("me")},
+		formPart{"avatar", "me.png", photo})
+
+	rules := map[string]UploadRule{"avatar": {MaxSize: 4 << 10, MIME: []string{"image/png"}}}
+	if err := CheckUploads(req, 1<<20, rules); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := req.ParseMultipartForm(1 << 20); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := req.FormValue("title"); got != "me" {
+		t.Errorf("got title %q, want me", got)
+	}
+
+	f, err := req.MultipartForm.File["avatar"][0].Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if got, _ := ioutil.ReadAll(f); !bytes.Equal(got, photo) {
+		t.Errorf("got %d bytes of the avatar, want the %d bytes sent", len(got), len(photo))
+	}
+
+	// a parsed form is checked through its file headers
+	expectCode(t, "parsed", CheckUploads(req, 0, map[string]UploadRule{
+		"avatar": {MaxSize: 10}}), "file.max_size")
+}
+
+func TestCheckUploadsSkipsOtherBodies(t *testing.T) {
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{"name": "ann"}`))
+	req.Header.Set("Content-Type", "application/json")
+	if err := CheckUploads(req, 4, map[string]UploadRule{"avatar": {MaxSize: 1}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if body, _ := ioutil.ReadAll(req.Body); string(body) != `{"name": "ann"}` {
+		t.Errorf("got body %q", body)
+	}
+
+	req = httptest.NewRequest("POST", "/", strings.NewReader("--x"))
+	req.Header.Set("Content-Type", "multipart/form-data")
+	expectCode(t, "no boundary", CheckUploads(req, 0, nil), "body.multipart")
+}
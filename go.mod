@@ -3,6 +3,7 @@ module github.com/rubikorg/blocks
 go 1.12
 
 require (
+	github.com/BurntSushi/toml v0.3.1
 	github.com/jordan-wright/email v0.0.0-20200521030443-c069f37d901d
 	github.com/rubikorg/rubik v0.0.0-20200601011723-1a305bdacac5
 	go.etcd.io/bbolt v1.3.4
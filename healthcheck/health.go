@@ -1,6 +1,8 @@
 package healthcheck
 
 import (
+	"encoding/json"
+	"net/http"
 	"strings"
 
 	"github.com/rubikorg/rubik"
@@ -10,38 +12,102 @@ import (
 // BlockName is the name of this block
 const BlockName = "HealthCheck"
 
-// BlockHealthCheck creates /health route for you and is used
-// on service health checkers like kubernetes etc ..
+// BlockHealthCheck creates the /health route and the /health/live,
+// /health/ready and /health/startup routes for you and is used on
+// service health checkers like kubernetes etc .. The paths are read from
+// the healthcheck table of the config, i.e:
+//
+//	[healthcheck]
+//	path = "/status"
+//	ready = "/status/ready"
+//
+// live, ready and startup default to sub paths of path
 type BlockHealthCheck struct {
 	customPath string
 }
 
 var hcRoute = r.Route{
-	Path: "/health",
-	Controller: func(req *rubik.Request) {
-		req.Respond("ok")
-	},
+	Path:        "/health",
+	Description: "Runs the registered health probes and reports their status",
+	Controller:  serveReport,
+}
+
+// checkRoutes are the routes of the single checks with their config key
+var checkRoutes = []struct {
+	key   string
+	route r.Route
+}{
+	{"live", r.Route{
+		Path:        "/live",
+		Description: "Runs the liveness probes, fails when the service must be restarted",
+		Controller:  serveCheck(Live),
+	}},
+	{"ready", r.Route{
+		Path:        "/ready",
+		Description: "Runs the readiness probes, fails while the service must not get traffic",
+		Controller:  serveCheck(Ready),
+	}},
+	{"startup", r.Route{
+		Path:        "/startup",
+		Description: "Runs the startup probes, fails until the service finished starting",
+		Controller:  serveCheck(Startup),
+	}},
+}
+
+// serveReport responds with the report of all probes, the status code is
+// 503 when a critical probe failed
+func serveReport(req *rubik.Request) {
+	writeReport(req, Check(req.Raw.Context()))
+}
+
+// serveCheck responds with the report of the probes of kind
+func serveCheck(kind Kind) r.Controller {
+	return func(req *r.Request) {
+		writeReport(req, CheckKind(req.Raw.Context(), kind))
+	}
+}
+
+func writeReport(req *r.Request, report Report) {
+	status := http.StatusOK
+	if report.Status == StatusFail {
+		status = http.StatusServiceUnavailable
+	}
+
+	req.Writer.Header().Set("Content-Type", "application/json")
+	req.Writer.Header().Set("Cache-Control", "no-store")
+	req.Writer.WriteHeader(status)
+	json.NewEncoder(req.Writer).Encode(report)
 }
 
 // OnAttach implementation for healthcheck block
 func (hc BlockHealthCheck) OnAttach(app *r.App) error {
-	conf := app.Config("healthcheck")
-	if conf == nil {
-		r.UseRoute(hcRoute)
-	} else {
-		c, ok := conf.(map[string]interface{})
-		if ok && c["path"].(string) != "" {
-			p := c["path"].(string)
-			if !strings.HasPrefix(c["path"].(string), "/") {
-				p = "/" + c["path"].(string)
-			}
-			hcRoute.Path = p
-			r.UseRoute(hcRoute)
-		}
+	c, _ := app.Config("healthcheck").(map[string]interface{})
+	base := configPath(c, "path", hcRoute.Path)
+	hcRoute.Path = base
+	r.UseRoute(hcRoute)
+
+	for _, cr := range checkRoutes {
+		route := cr.route
+		route.Path = configPath(c, cr.key, strings.TrimSuffix(base, "/")+route.Path)
+		r.UseRoute(route)
 	}
 	return nil
 }
 
+// configPath returns the route path under key of the config or def when
+// it is not set
+func configPath(c map[string]interface{}, key, def string) string {
+	p, _ := c[key].(string)
+	if p == "" {
+		p = def
+	}
+
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	return p
+}
+
 func init() {
 	r.Attach(BlockName, BlockHealthCheck{})
 }
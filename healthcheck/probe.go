@@ -0,0 +1,286 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultTimeout is the time a probe gets when its options do not set
+// one
+const DefaultTimeout = 5 * time.Second
+
+// Probe checks one dependency of the service and returns an error if it
+// is not healthy, it should give up once ctx is done
+type Probe func(ctx context.Context) error
+
+// Kind selects the health checks a probe takes part in, kinds can be
+// combined like Live | Ready
+type Kind int
+
+// The checks served by the live, ready and startup routes
+const (
+	Live Kind = 1 << iota
+	Ready
+	Startup
+)
+
+// Options configures a registered probe
+type Options struct {
+	// Critical probes turn the health route into a 503 when they fail,
+	// the failure of other probes is only reported
+	Critical bool
+	// Timeout bounds a single run of the probe, DefaultTimeout is used
+	// when it is zero
+	Timeout time.Duration
+	// Checks are the kinds of checks running the probe, any failing
+	// probe of a check fails it whether it is critical or not. A probe
+	// without kinds takes part in the readiness check if it is critical
+	// and in none otherwise. The health route runs every probe
+	Checks Kind
+}
+
+// Status of a probe or of the whole report
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+	StatusFail     = "fail"
+)
+
+// Result is the outcome of a single probe run
+type Result struct {
+	Status   string `json:"status"`
+	Critical bool   `json:"critical"`
+	Duration string `json:"duration"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Report is the body of the health routes. Status is fail if a critical
+// probe failed, degraded if another probe failed and ok otherwise, the
+// live, ready and startup reports have no degraded status. Message tells
+// why a check failed without running its probes
+type Report struct {
+	Status  string            `json:"status"`
+	Message string            `json:"message,omitempty"`
+	Probes  map[string]Result `json:"probes"`
+}
+
+type registeredProbe struct {
+	probe Probe
+	opts  Options
+}
+
+var probes = struct {
+	sync.RWMutex
+	m map[string]registeredProbe
+}{m: make(map[string]registeredProbe)}
+
+// Register adds a probe which runs every time the health route or one of
+// the routes of its checks is called, registering an existing name
+// replaces its probe, i.e:
+//
+//	healthcheck.Register("database", healthcheck.PingProbe(db),
+//		healthcheck.Options{Critical: true, Checks: healthcheck.Ready | healthcheck.Startup})
+func Register(name string, probe Probe, opts Options) error {
+	if name == "" {
+		return errors.New("healthcheck: probe needs a name")
+	}
+
+	if probe == nil {
+		return errors.New("healthcheck: probe " + name + " is nil")
+	}
+
+	probes.Lock()
+	probes.m[name] = registeredProbe{probe, opts}
+	probes.Unlock()
+	return nil
+}
+
+// Unregister removes the probe with the given name
+func Unregister(name string) {
+	probes.Lock()
+	delete(probes.m, name)
+	probes.Unlock()
+}
+
+// notReady and started hold the state set by SetReady and MarkStarted,
+// the service is ready from the start but has to tell when it started
+var notReady, started int32
+
+// SetReady toggles the readiness check, the ready route fails without
+// running its probes while the service is not ready, i.e: during warmup
+// or while draining connections before shutdown
+func SetReady(ready bool) {
+	var v int32
+	if !ready {
+		v = 1
+	}
+	atomic.StoreInt32(&notReady, v)
+}
+
+// IsReady tells if the readiness check is toggled on
+func IsReady() bool {
+	return atomic.LoadInt32(&notReady) == 0
+}
+
+// MarkStarted tells the startup check that the service finished its
+// initialization, the startup route fails until it is called
+func MarkStarted() {
+	atomic.StoreInt32(&started, 1)
+}
+
+// HasStarted tells if MarkStarted was called
+func HasStarted() bool {
+	return atomic.LoadInt32(&started) == 1
+}
+
+// Check runs all registered probes concurrently and returns their report
+func Check(ctx context.Context) Report {
+	return check(ctx, 0, false)
+}
+
+// CheckKind runs the probes taking part in the given kind of check, the
+// check fails if any of them fails. The readiness check fails while
+// SetReady is off and the startup check until MarkStarted is called,
+// their probes do not run then
+func CheckKind(ctx context.Context, kind Kind) Report {
+	switch {
+	case kind&Ready != 0 && !IsReady():
+		return Report{Status: StatusFail, Message: "not ready", Probes: map[string]Result{}}
+	case kind&Startup != 0 && !HasStarted():
+		return Report{Status: StatusFail, Message: "starting", Probes: map[string]Result{}}
+	}
+
+	return check(ctx, kind, true)
+}
+
+// check runs the probes of kind or every probe when kind is 0, strict
+// fails the report on any failing probe instead of only on critical ones
+func check(ctx context.Context, kind Kind, strict bool) Report {
+	probes.RLock()
+	names := make([]string, 0, len(probes.m))
+	for name, rp := range probes.m {
+		if kind == 0 || rp.kinds()&kind != 0 {
+			names = append(names, name)
+		}
+	}
+	registered := make([]registeredProbe, len(names))
+	sort.Strings(names)
+	for i, name := range names {
+		registered[i] = probes.m[name]
+	}
+	probes.RUnlock()
+
+	results := make([]Result, len(names))
+	var wg sync.WaitGroup
+	for i := range registered {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = run(ctx, registered[i])
+		}(i)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Probes: make(map[string]Result, len(names))}
+	for i, name := range names {
+		res := results[i]
+		report.Probes[name] = res
+		if res.Status == StatusOK {
+			continue
+		}
+
+		if res.Critical || strict {
+			report.Status = StatusFail
+		} else if report.Status == StatusOK {
+			report.Status = StatusDegraded
+		}
+	}
+
+	return report
+}
+
+func (rp registeredProbe) kinds() Kind {
+	if rp.opts.Checks != 0 {
+		return rp.opts.Checks
+	}
+
+	if rp.opts.Critical {
+		return Ready
+	}
+
+	return 0
+}
+
+// run calls the probe with its timeout, a panicking probe is reported as
+// failed instead of taking the service down
+func run(ctx context.Context, rp registeredProbe) Result {
+	timeout := rp.opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				done <- fmt.Errorf("probe panicked: %v", rec)
+			}
+		}()
+		done <- rp.probe(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = errors.New("timed out after " + timeout.String())
+	}
+
+	res := Result{
+		Status:   StatusOK,
+		Critical: rp.opts.Critical,
+		Duration: time.Since(start).String(),
+	}
+	if err != nil {
+		res.Status = StatusFail
+		res.Error = err.Error()
+	}
+
+	return res
+}
+
+// Pinger is satisfied by *sql.DB and other clients which can check their
+// connection
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// PingProbe checks the connection of a database or any other Pinger
+func PingProbe(p Pinger) Probe {
+	return p.PingContext
+}
+
+// TCPProbe checks that addr accepts TCP connections, it stands in for
+// services without a health API like an SMTP server, i.e:
+// TCPProbe("smtp.example.com:587")
+func TCPProbe(addr string) Probe {
+	return func(ctx context.Context) error {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+
+		return conn.Close()
+	}
+}
@@ -0,0 +1,147 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// withProbes registers the probes for the test and removes them after
+func withProbes(t *testing.T, probes map[string]registeredProbe) func() {
+	t.Helper()
+	for name, rp := range probes {
+		if err := Register(name, rp.probe, rp.opts); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return func() {
+		for name := range probes {
+			Unregister(name)
+		}
+	}
+}
+
+func healthy(context.Context) error { return nil }
+
+func failing(context.Context) error { return errors.New("down") }
+
+func TestCheck(t *testing.T) {
+	defer withProbes(t, map[string]registeredProbe{
+		"db":    {healthy, Options{Critical: true}},
+		"cache": {failing, Options{}},
+	})()
+
+	report := Check(context.Background())
+	if report.Status != StatusDegraded {
+		t.Errorf("non critical failure: got %s, want %s", report.Status, StatusDegraded)
+	}
+
+	if res := report.Probes["cache"]; res.Status != StatusFail || res.Error != "down" {
+		t.Errorf("cache: got %+v", res)
+	}
+
+	Register("db", failing, Options{Critical: true})
+	if report := Check(context.Background()); report.Status != StatusFail {
+		t.Errorf("critical failure: got %s, want %s", report.Status, StatusFail)
+	}
+}
+
+func TestCheckTimeoutAndPanic(t *testing.T) {
+	defer withProbes(t, map[string]registeredProbe{
+		"slow": {func(ctx context.Context) error {
+			<-ctx.Done()
+			time.Sleep(50 * time.Millisecond)
+			return nil
+		}, Options{Timeout: 10 * time.Millisecond}},
+		"panics": {func(context.Context) error { panic("boom") }, Options{}},
+	})()
+
+	start := time.Now()
+	report := Check(context.Background())
+	if elapsed := time.Since(start); elapsed > 40*time.Millisecond {
+		t.Errorf("check waited %s for a timed out probe", elapsed)
+	}
+
+	if res := report.Probes["slow"]; res.Status != StatusFail || res.Error != "timed out after 10ms" {
+		t.Errorf("slow: got %+v", res)
+	}
+
+	if res := report.Probes["panics"]; res.Status != StatusFail || res.Error != "probe panicked: boom" {
+		t.Errorf("panics: got %+v", res)
+	}
+}
+
+func TestCheckKind(t *testing.T) {
+	defer withProbes(t, map[string]registeredProbe{
+		"loop":    {healthy, Options{Checks: Live}},
+		"db":      {healthy, Options{Critical: true}},
+		"search":  {failing, Options{Checks: Ready}},
+		"migrate": {healthy, Options{Checks: Startup | Ready}},
+		"cache":   {failing, Options{}},
+	})()
+	defer SetReady(true)
+	atomic.StoreInt32(&started, 0)
+	ctx := context.Background()
+
+	live := CheckKind(ctx, Live)
+	if live.Status != StatusOK || len(live.Probes) != 1 || live.Probes["loop"].Status != StatusOK {
+		t.Errorf("live: got %+v", live)
+	}
+
+	ready := CheckKind(ctx, Ready)
+	if ready.Status != StatusFail {
+		t.Errorf("a failing non critical ready probe passed: %+v", ready)
+	}
+	for _, name := range []string{"db", "search", "migrate"} {
+		if _, ok := ready.Probes[name]; !ok {
+			t.Errorf("ready did not run %s", name)
+		}
+	}
+	if _, ok := ready.Probes["cache"]; ok {
+		t.Error("ready ran a non critical probe without checks")
+	}
+
+	Unregister("search")
+	if ready := CheckKind(ctx, Ready); ready.Status != StatusOK {
+		t.Errorf("ready: got %+v", ready)
+	}
+
+	SetReady(false)
+	if ready := CheckKind(ctx, Ready); ready.Status != StatusFail || len(ready.Probes) != 0 {
+		t.Errorf("ready while toggled off: got %+v", ready)
+	}
+	SetReady(true)
+
+	if startup := CheckKind(ctx, Startup); startup.Status != StatusFail || startup.Message == "" {
+		t.Errorf("startup before MarkStarted: got %+v", startup)
+	}
+	MarkStarted()
+	startup := CheckKind(ctx, Startup)
+	if startup.Status != StatusOK || len(startup.Probes) != 1 {
+		t.Errorf("startup: got %+v", startup)
+	}
+}
+
+func TestConfigPath(t *testing.T) {
+	conf := map[string]interface{}{"path": "status", "ready": "/ready"}
+	tests := []struct {
+		key, def, want string
+	}{
+		{"path", "/health", "/status"},
+		{"ready", "/status/ready", "/ready"},
+		{"live", "/status/live", "/status/live"},
+	}
+
+	for _, tt := range tests {
+		if got := configPath(conf, tt.key, tt.def); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.key, got, tt.want)
+		}
+	}
+
+	if got := configPath(nil, "path", "/health"); got != "/health" {
+		t.Errorf("without config: got %s", got)
+	}
+}
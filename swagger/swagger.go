@@ -2,8 +2,11 @@ package swagger
 
 import (
 	"net/http"
+	"reflect"
 	"strings"
 
+	"github.com/rubikorg/blocks/apigen/sdkdist"
+	"github.com/rubikorg/blocks/checker"
 	r "github.com/rubikorg/rubik"
 )
 
@@ -144,7 +147,7 @@ func insertPaths(ri []r.RouteInfo) {
 			method: {
 				Tags:       []string{belongsTo},
 				Summary:    info.Description,
-				Parameters: []swagParams{},
+				Parameters: entityParams(info.Entity),
 				Produces:   []string{"application/json"},
 				Responses:  responses,
 			},
@@ -153,6 +156,143 @@ func insertPaths(ri []r.RouteInfo) {
 	}
 }
 
+// entityParams lists the fields of the entity as parameters, the rules
+// of the checker block for the fields are added as constraints
+func entityParams(entity interface{}) []swagParams {
+	params := []swagParams{}
+	rt := reflect.TypeOf(entity)
+	for rt != nil && rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+
+	if rt == nil || rt.Kind() != reflect.Struct {
+		return params
+	}
+
+	rules, _ := checker.DescribeEntity(entity)
+	body := &swagSchema{Type: "object", Properties: make(map[string]*swagSchema)}
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.Anonymous || field.PkgPath != "" {
+			continue
+		}
+
+		name, in := requestField(field)
+		keywords := checker.Keywords(rules.Lookup(name, jsonName(field), field.Name))
+		required := keywords["required"] == true
+		typ, format := swagType(field.Type)
+		if f, ok := keywords["format"].(string); ok && typ == "string" {
+			format = f
+		}
+
+		if in == "body" {
+			prop := &swagSchema{Type: typ, Format: format, swagConstraints: constraints(keywords)}
+			body.Properties[name] = prop
+			if required {
+				body.Required = append(body.Required, name)
+			}
+			continue
+		}
+
+		params = append(params, swagParams{
+			Name:            name,
+			In:              in,
+			Required:        required || in == "path",
+			Type:            typ,
+			Format:          format,
+			swagConstraints: constraints(keywords),
+		})
+	}
+
+	if len(body.Properties) > 0 {
+		params = append(params, swagParams{
+			Name:     "body",
+			In:       "body",
+			Required: len(body.Required) > 0,
+			Schema:   body,
+		})
+	}
+
+	return params
+}
+
+// requestField reads the name and the medium of a field from its
+// `rubik` tag like `name|body` the way the generated SDKs do, fields are
+// query parameters by default
+func requestField(field reflect.StructField) (string, string) {
+	name, medium := sdkdist.RequestField(field.Name, field.Tag.Get("rubik"))
+	switch medium {
+	case "param":
+		medium = "path"
+	case "form":
+		medium = "formData"
+	}
+
+	return name, medium
+}
+
+func jsonName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+
+	return name
+}
+
+func swagType(rt reflect.Type) (string, string) {
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+
+	switch rt.Kind() {
+	case reflect.String:
+		return "string", ""
+	case reflect.Bool:
+		return "boolean", ""
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return "integer", "int32"
+	case reflect.Int64, reflect.Uint64:
+		return "integer", "int64"
+	case reflect.Float32:
+		return "number", "float"
+	case reflect.Float64:
+		return "number", "double"
+	case reflect.Slice, reflect.Array:
+		return "array", ""
+	}
+
+	return "object", ""
+}
+
+// constraints turns the JSON Schema keywords of the checker rules into
+// their swagger 2.0 form
+func constraints(keywords map[string]interface{}) swagConstraints {
+	c := swagConstraints{
+		Enum:        keywords["enum"],
+		MinLength:   keywords["minLength"],
+		MaxLength:   keywords["maxLength"],
+		Minimum:     keywords["minimum"],
+		Maximum:     keywords["maximum"],
+		MultipleOf:  keywords["multipleOf"],
+		MinItems:    keywords["minItems"],
+		MaxItems:    keywords["maxItems"],
+		UniqueItems: keywords["uniqueItems"] == true,
+	}
+
+	if p, ok := keywords["pattern"].(string); ok {
+		c.Pattern = p
+	}
+
+	if min, ok := keywords["exclusiveMinimum"]; ok {
+		c.Minimum = min
+		c.ExclusiveMinimum = true
+	}
+
+	return c
+}
+
 func insertSwaggerTags(rl map[string]string) {
 	for k, v := range rl {
 		name := k
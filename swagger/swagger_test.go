@@ -0,0 +1,42 @@
+package swagger
+
+import (
+	"testing"
+
+	"github.com/rubikorg/blocks/apigen/sdkdist"
+)
+
+func TestEntityParamsNamedLikeSDK(t *testing.T) {
+	type signup struct {
+		Username string
+		Email    string `json:"mail" rubik:"email|body"`
+		Slug     string `rubik:"param"`
+		Avatar   string `rubik:"|form"`
+	}
+
+	params := entityParams(signup{})
+	want := map[string]string{
+		"username": "query",
+		"slug":     "path",
+		"avatar":   "formData",
+		"body":     "body",
+	}
+	if len(params) != len(want) {
+		t.Fatalf("got %d params, want %d: %+v", len(params), len(want), params)
+	}
+
+	for _, p := range params {
+		if want[p.Name] != p.In {
+			t.Errorf("param %s in %s, want %q", p.Name, p.In, want[p.Name])
+		}
+	}
+
+	body := params[len(params)-1].Schema
+	if body == nil || body.Properties["email"] == nil {
+		t.Errorf("body does not have the email property: %+v", body)
+	}
+
+	if key, _ := sdkdist.RequestField("Username", ""); key != "username" {
+		t.Errorf("sdk names Username as %s", key)
+	}
+}
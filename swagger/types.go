@@ -22,12 +22,39 @@ type swagPathInfo struct {
 }
 
 type swagParams struct {
-	Name        string `json:"name"`
-	In          string `json:"in"`
-	Description string `json:"description"`
-	Required    bool   `json:"required"`
-	Type        string `json:"type"`
-	Format      string `json:"format"`
+	Name        string      `json:"name"`
+	In          string      `json:"in"`
+	Description string      `json:"description"`
+	Required    bool        `json:"required"`
+	Type        string      `json:"type,omitempty"`
+	Format      string      `json:"format,omitempty"`
+	Schema      *swagSchema `json:"schema,omitempty"`
+	swagConstraints
+}
+
+// swagSchema is the schema of the body parameter
+type swagSchema struct {
+	Type       string                 `json:"type"`
+	Format     string                 `json:"format,omitempty"`
+	Properties map[string]*swagSchema `json:"properties,omitempty"`
+	Required   []string               `json:"required,omitempty"`
+	swagConstraints
+}
+
+// swagConstraints are the validation keywords of a parameter or a
+// property, they are filled from the checker rules of the entity
+type swagConstraints struct {
+	Enum             interface{} `json:"enum,omitempty"`
+	Pattern          string      `json:"pattern,omitempty"`
+	MinLength        interface{} `json:"minLength,omitempty"`
+	MaxLength        interface{} `json:"maxLength,omitempty"`
+	Minimum          interface{} `json:"minimum,omitempty"`
+	Maximum          interface{} `json:"maximum,omitempty"`
+	ExclusiveMinimum bool        `json:"exclusiveMinimum,omitempty"`
+	MultipleOf       interface{} `json:"multipleOf,omitempty"`
+	MinItems         interface{} `json:"minItems,omitempty"`
+	MaxItems         interface{} `json:"maxItems,omitempty"`
+	UniqueItems      bool        `json:"uniqueItems,omitempty"`
 }
 
 type swagResponse struct {